@@ -0,0 +1,46 @@
+// Package api содержит OpenAPI-спецификацию HTTP API сервиса
+package api
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed openapi.yaml
+var spec []byte
+
+// swaggerUI - статика Swagger UI для /docs: swagger-ui-bundle.js и swagger-ui.css из
+// swagger-ui-dist 5.18.2 (лицензия Apache 2.0) лежат в репозитории, поэтому документация
+// не зависит от внешнего CDN
+//
+//go:embed swagger-ui
+var swaggerUI embed.FS
+
+// SwaggerUI возвращает встроенные файлы Swagger UI
+func SwaggerUI() fs.FS {
+	assets, err := fs.Sub(swaggerUI, "swagger-ui")
+	if err != nil {
+		panic(err) // каталог встроен при сборке
+	}
+	return assets
+}
+
+// LoadSpec загружает и валидирует встроенную OpenAPI-спецификацию
+func LoadSpec() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("validate openapi spec: %w", err)
+	}
+
+	return doc, nil
+}
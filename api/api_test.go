@@ -0,0 +1,19 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chilly266futon/reviewer-assignment-service/api"
+)
+
+func TestLoadSpec(t *testing.T) {
+	doc, err := api.LoadSpec()
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+
+	assert.NotNil(t, doc.Paths.Find("/pullRequest/create"))
+	assert.NotNil(t, doc.Components.Schemas["ErrorResponse"])
+}
@@ -4,16 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // база часовых поясов для расписаний пользователей (в alpine-образе её нет)
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
+	"github.com/chilly266futon/reviewer-assignment-service/api"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/config"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/grpcserver"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/handler"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/repository/postgres"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
 	"github.com/chilly266futon/reviewer-assignment-service/pkg/logger"
@@ -37,6 +43,7 @@ func main() {
 
 	log.Info("Starting reviewer-assignment-service...",
 		zap.String("port", cfg.ServerPort),
+		zap.String("grpc_port", cfg.GRPCPort),
 		zap.String("log_level", cfg.LogLevel),
 	)
 
@@ -61,20 +68,104 @@ func main() {
 	// Создаем transaction manager и репозитории
 	txManager := postgres.NewTxManager(pool)
 	userRepo := postgres.NewUserRepository(pool, log)
-	teamRepo := postgres.NewTeamRepository(pool, log)
+	teamRepo := postgres.NewTeamRepository(pool, txManager, log)
 	prRepo := postgres.NewPRRepository(pool, txManager, log)
+	idempotencyRepo := postgres.NewIdempotencyRepository(pool, txManager, log)
+	absenceRepo := postgres.NewAbsenceRepository(pool, log)
+	ruleRepo := postgres.NewReviewerRuleRepository(pool, log)
+	jobRepo := postgres.NewJobRepository(pool, txManager, log)
 
 	log.Info("repositories initialized")
 
+	// Каналы уведомлений. Сообщения в чат отправляются командам, задавшим вебхук;
+	// события, не нужные ни одному каналу, не собираются
+	var (
+		eventNotifiers  = notify.EventNotifiers{notify.NewChatNotifier(cfg.PRURLTemplate, cfg.NotifyTimeout, log)}
+		digestNotifiers notify.DigestNotifiers
+	)
+	if cfg.DigestWebhookURL != "" {
+		digestNotifiers = append(digestNotifiers, notify.NewWebhookNotifier(cfg.DigestWebhookURL, cfg.NotifyTimeout, log))
+	}
+	if cfg.SMTPHost != "" {
+		smtpNotifier, err := notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:         cfg.SMTPHost,
+			Port:         cfg.SMTPPort,
+			Username:     cfg.SMTPUsername,
+			Password:     cfg.SMTPPassword,
+			From:         cfg.SMTPFrom,
+			TLS:          cfg.SMTPTLS,
+			Timeout:      cfg.NotifyTimeout,
+			TemplatesDir: cfg.EmailTemplatesDir,
+		}, log)
+		if err != nil {
+			log.Fatal("failed to create smtp notifier", zap.Error(err))
+		}
+		eventNotifiers = append(eventNotifiers, smtpNotifier)
+		digestNotifiers = append(digestNotifiers, smtpNotifier)
+	} else {
+		log.Info("email notifications disabled: SMTP_HOST is not set")
+	}
+
 	// Инициализируем сервисы
-	teamService := service.NewTeamService(teamRepo, userRepo, log)
-	userService := service.NewUserService(userRepo, prRepo, log)
-	prService := service.NewPRService(prRepo, userRepo, log)
+	teamService := service.NewTeamService(teamRepo, userRepo, absenceRepo, ruleRepo, log)
+	userService := service.NewUserService(userRepo, prRepo, absenceRepo, log)
+	prService := service.NewPRService(
+		prRepo,
+		userRepo,
+		teamRepo,
+		ruleRepo,
+		eventNotifiers,
+		cfg.AssignmentMode,
+		cfg.ReviewerRotationWindow,
+		log,
+	)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyTTL, cfg.IdempotencyLockTTL, log)
+	slaService := service.NewSLAService(prRepo, prService, log)
+
+	// Фоновые задачи: каждую в один момент выполняет только один экземпляр сервиса
+	scheduler := service.NewScheduler(jobRepo, log)
+	scheduler.Register(&service.Job{
+		Name:        service.JobReviewSLA,
+		Description: "Escalate reviews overdue per team review SLA",
+		Interval:    cfg.SLACheckInterval,
+		Run:         slaService.EscalateOverdueReviews,
+	})
+	scheduler.Register(&service.Job{
+		Name:        service.JobIdempotencyCleanup,
+		Description: "Delete expired idempotency keys",
+		Interval:    cfg.IdempotencyCleanupInterval,
+		Run: func(ctx context.Context, _ time.Time) (int, error) {
+			return idempotencyService.DeleteExpired(ctx)
+		},
+	})
+
+	if len(digestNotifiers) > 0 {
+		digestService := service.NewDigestService(userRepo, prRepo, digestNotifiers, cfg.DigestPeriod, log)
+		scheduler.Register(&service.Job{
+			Name:        service.JobReviewDigest,
+			Description: "Send users a digest of open pull requests awaiting their review",
+			Interval:    cfg.DigestCheckInterval,
+			Run:         digestService.SendDigests,
+		})
+	} else {
+		log.Info("review digests disabled: neither DIGEST_WEBHOOK_URL nor SMTP_HOST is set")
+	}
 
 	log.Info("services initialized")
 
+	// Загружаем OpenAPI-спецификацию
+	openAPIDoc, err := api.LoadSpec()
+	if err != nil {
+		log.Fatal("failed to load openapi spec", zap.Error(err))
+	}
+
+	openAPIHandler, err := handler.NewOpenAPIHandler(openAPIDoc, api.SwaggerUI(), log)
+	if err != nil {
+		log.Fatal("failed to create openapi handler", zap.Error(err))
+	}
+
 	// Создаем router
-	router := handler.NewRouter(teamService, userService, prService, pool, log)
+	router := handler.NewRouter(cfg, teamService, userService, prService, idempotencyService, scheduler, openAPIHandler, pool, log)
 
 	log.Info("router configured")
 
@@ -87,6 +178,9 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Запускаем фоновые задачи по расписанию
+	scheduler.Start(ctx)
+
 	// Запускаем сервер в горутине
 	go func() {
 		log.Info("Server listening", zap.String("addr", srv.Addr))
@@ -95,6 +189,21 @@ func main() {
 		}
 	}()
 
+	// Создаем gRPC сервер
+	grpcSrv := grpcserver.NewServer(teamService, userService, prService, log)
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatal("failed to listen grpc port", zap.Error(err))
+	}
+
+	go func() {
+		log.Info("gRPC server listening", zap.String("addr", grpcListener.Addr().String()))
+		if err := grpcSrv.Serve(grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Fatal("gRPC server failed to start", zap.Error(err))
+		}
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -102,12 +211,34 @@ func main() {
 
 	log.Info("Shutting down server...")
 
+	// Останавливаем фоновые задачи
+	cancel()
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
+	// gRPC сервер останавливаем параллельно с HTTP, принудительно - по истечении таймаута
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(grpcStopped)
+	}()
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		log.Warn("gRPC server forced to shutdown")
+		grpcSrv.Stop()
+	}
+
+	// Дожидаемся выполняющихся фоновых задач и отправки уведомлений, пока пул соединений ещё открыт.
+	// Уведомления ждём после задач: эскалация SLA тоже их отправляет
+	scheduler.Wait()
+	prService.Wait()
+
 	log.Info("Server stopped gracefully")
 }
@@ -2,13 +2,20 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v10"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
 )
 
 type Config struct {
 	// Server
 	ServerPort string `env:"SERVER_PORT" envDefault:"8080"`
+	GRPCPort   string `env:"GRPC_PORT" envDefault:"9090"`
 
 	// Database
 	DBHost     string `env:"DB_HOST,required"`
@@ -18,6 +25,51 @@ type Config struct {
 	DBName     string `env:"DB_NAME,required"`
 	DBSSLMode  string `env:"DB_SSL_MODE" envDefault:"disable"`
 
+	// Idempotency
+	IdempotencyTTL time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+	// Сколько запрос может держать ключ без ответа. Ключ, который дольше не завершён (например, процесс упал),
+	// может занять повторный запрос. Должно быть больше времени обработки запроса
+	IdempotencyLockTTL time.Duration `env:"IDEMPOTENCY_LOCK_TTL" envDefault:"1m"`
+
+	// OpenAPI
+	OpenAPIValidateRequests  bool `env:"OPENAPI_VALIDATE_REQUESTS" envDefault:"true"`
+	OpenAPIValidateResponses bool `env:"OPENAPI_VALIDATE_RESPONSES" envDefault:"false"`
+
+	// Assignment
+	// random - случайный выбор; working_hours - приоритет ревьюерам, у которых сейчас рабочее время
+	AssignmentMode domain.AssignmentMode `env:"ASSIGNMENT_MODE" envDefault:"random"`
+	// Ревьюеры, недавно (за это окно) ревьюившие PR того же автора, выбираются в последнюю очередь. 0 - отключено
+	ReviewerRotationWindow time.Duration `env:"REVIEWER_ROTATION_WINDOW" envDefault:"0s"`
+
+	// Background jobs
+	// Интервалы запуска фоновых задач. 0 - задача запускается только вручную (/admin/triggerJob)
+	SLACheckInterval           time.Duration `env:"SLA_CHECK_INTERVAL" envDefault:"5m"`
+	IdempotencyCleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" envDefault:"1h"`
+	DigestCheckInterval        time.Duration `env:"DIGEST_CHECK_INTERVAL" envDefault:"1h"`
+
+	// Review digests
+	// Сводка ожидающих ревью отправляется пользователю не чаще раза за DigestPeriod
+	DigestPeriod time.Duration `env:"DIGEST_PERIOD" envDefault:"24h"`
+	// URL, на который POST-запросом отправляются сводки. Пусто - сводки не отправляются вебхуком
+	DigestWebhookURL string `env:"DIGEST_WEBHOOK_URL"`
+
+	// Notifications
+	// Таймаут отправки одного уведомления
+	NotifyTimeout time.Duration `env:"NOTIFY_TIMEOUT" envDefault:"10s"`
+	// Шаблон ссылки на PR в сообщениях чата, {id} заменяется на ID PR. Пусто - сообщения без ссылок
+	PRURLTemplate string `env:"PR_URL_TEMPLATE"`
+
+	// Email (SMTP)
+	// Письма о назначении ревьюером и сводки. Пустой SMTPHost - письма не отправляются
+	SMTPHost     string             `env:"SMTP_HOST"`
+	SMTPPort     int                `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string             `env:"SMTP_USERNAME"` // пусто - без аутентификации
+	SMTPPassword string             `env:"SMTP_PASSWORD"`
+	SMTPFrom     string             `env:"SMTP_FROM"` // обязателен, если задан SMTPHost
+	SMTPTLS      notify.SMTPTLSMode `env:"SMTP_TLS" envDefault:"starttls"`
+	// Каталог с шаблонами писем вместо встроенных. Пусто - встроенные шаблоны
+	EmailTemplatesDir string `env:"EMAIL_TEMPLATES_DIR"`
+
 	//Logging
 	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
 }
@@ -28,5 +80,53 @@ func Load() (*Config, error) {
 	if err := env.Parse(cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
+	if !cfg.AssignmentMode.IsValid() {
+		return nil, fmt.Errorf("unknown ASSIGNMENT_MODE: %q", cfg.AssignmentMode)
+	}
+	if cfg.ReviewerRotationWindow < 0 {
+		return nil, fmt.Errorf("REVIEWER_ROTATION_WINDOW must not be negative")
+	}
+	if cfg.SLACheckInterval < 0 {
+		return nil, fmt.Errorf("SLA_CHECK_INTERVAL must not be negative")
+	}
+	if cfg.IdempotencyLockTTL <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_LOCK_TTL must be positive")
+	}
+	if cfg.IdempotencyCleanupInterval < 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_CLEANUP_INTERVAL must not be negative")
+	}
+	if cfg.DigestCheckInterval < 0 {
+		return nil, fmt.Errorf("DIGEST_CHECK_INTERVAL must not be negative")
+	}
+	if cfg.DigestPeriod <= 0 {
+		return nil, fmt.Errorf("DIGEST_PERIOD must be positive")
+	}
+	if cfg.DigestWebhookURL != "" {
+		if err := domain.ValidateWebhookURL(cfg.DigestWebhookURL); err != nil {
+			return nil, fmt.Errorf("invalid DIGEST_WEBHOOK_URL: %w", err)
+		}
+	}
+	if cfg.NotifyTimeout <= 0 {
+		return nil, fmt.Errorf("NOTIFY_TIMEOUT must be positive")
+	}
+	if cfg.PRURLTemplate != "" {
+		if !strings.Contains(cfg.PRURLTemplate, notify.PRURLPlaceholder) {
+			return nil, fmt.Errorf("PR_URL_TEMPLATE must contain %s", notify.PRURLPlaceholder)
+		}
+		if err := domain.ValidateWebhookURL(strings.ReplaceAll(cfg.PRURLTemplate, notify.PRURLPlaceholder, "id")); err != nil {
+			return nil, fmt.Errorf("invalid PR_URL_TEMPLATE: %w", err)
+		}
+	}
+	if cfg.SMTPHost != "" {
+		if cfg.SMTPPort <= 0 || cfg.SMTPPort > 65535 {
+			return nil, fmt.Errorf("invalid SMTP_PORT: %d", cfg.SMTPPort)
+		}
+		if _, err := mail.ParseAddress(cfg.SMTPFrom); err != nil {
+			return nil, fmt.Errorf("invalid SMTP_FROM: %w", err)
+		}
+		if !cfg.SMTPTLS.IsValid() {
+			return nil, fmt.Errorf("invalid SMTP_TLS: %s", cfg.SMTPTLS)
+		}
+	}
 	return cfg, nil
 }
@@ -3,11 +3,14 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/chilly266futon/reviewer-assignment-service/internal/config"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
 )
 
 func TestLoad_Success(t *testing.T) {
@@ -32,9 +35,27 @@ func TestLoad_Success(t *testing.T) {
 	assert.Equal(t, "testpass", cfg.DBPassword)
 	assert.Equal(t, "testdb", cfg.DBName)
 	assert.Equal(t, "8080", cfg.ServerPort)
+	assert.Equal(t, "9090", cfg.GRPCPort)
 	assert.Equal(t, "5432", cfg.DBPort)
 	assert.Equal(t, "disable", cfg.DBSSLMode)
 	assert.Equal(t, "info", cfg.LogLevel)
+	assert.Equal(t, 24*time.Hour, cfg.IdempotencyTTL)
+	assert.Equal(t, time.Minute, cfg.IdempotencyLockTTL)
+	assert.True(t, cfg.OpenAPIValidateRequests)
+	assert.False(t, cfg.OpenAPIValidateResponses)
+	assert.Equal(t, domain.AssignmentModeRandom, cfg.AssignmentMode)
+	assert.Zero(t, cfg.ReviewerRotationWindow)
+	assert.Equal(t, 5*time.Minute, cfg.SLACheckInterval)
+	assert.Equal(t, time.Hour, cfg.IdempotencyCleanupInterval)
+	assert.Equal(t, time.Hour, cfg.DigestCheckInterval)
+	assert.Equal(t, 24*time.Hour, cfg.DigestPeriod)
+	assert.Empty(t, cfg.DigestWebhookURL)
+	assert.Equal(t, 10*time.Second, cfg.NotifyTimeout)
+	assert.Empty(t, cfg.PRURLTemplate)
+	assert.Empty(t, cfg.SMTPHost)
+	assert.Equal(t, 587, cfg.SMTPPort)
+	assert.Equal(t, notify.SMTPTLSStartTLS, cfg.SMTPTLS)
+	assert.Empty(t, cfg.EmailTemplatesDir)
 }
 
 func TestLoad_CustomValues(t *testing.T) {
@@ -45,7 +66,28 @@ func TestLoad_CustomValues(t *testing.T) {
 	os.Setenv("DB_NAME", "customdb")
 	os.Setenv("DB_SSL_MODE", "require")
 	os.Setenv("SERVER_PORT", "9090")
+	os.Setenv("GRPC_PORT", "9191")
 	os.Setenv("LOG_LEVEL", "debug")
+	os.Setenv("IDEMPOTENCY_TTL", "1h")
+	os.Setenv("IDEMPOTENCY_LOCK_TTL", "30s")
+	os.Setenv("OPENAPI_VALIDATE_REQUESTS", "false")
+	os.Setenv("OPENAPI_VALIDATE_RESPONSES", "true")
+	os.Setenv("ASSIGNMENT_MODE", "working_hours")
+	os.Setenv("REVIEWER_ROTATION_WINDOW", "168h")
+	os.Setenv("SLA_CHECK_INTERVAL", "0s")
+	os.Setenv("IDEMPOTENCY_CLEANUP_INTERVAL", "30m")
+	os.Setenv("DIGEST_CHECK_INTERVAL", "15m")
+	os.Setenv("DIGEST_PERIOD", "12h")
+	os.Setenv("DIGEST_WEBHOOK_URL", "https://hooks.example.com/digest")
+	os.Setenv("NOTIFY_TIMEOUT", "3s")
+	os.Setenv("PR_URL_TEMPLATE", "https://git.example.com/pulls/{id}")
+	os.Setenv("SMTP_HOST", "smtp.example.com")
+	os.Setenv("SMTP_PORT", "465")
+	os.Setenv("SMTP_USERNAME", "bot")
+	os.Setenv("SMTP_PASSWORD", "secret")
+	os.Setenv("SMTP_FROM", "Reviewer Bot <bot@example.com>")
+	os.Setenv("SMTP_TLS", "tls")
+	os.Setenv("EMAIL_TEMPLATES_DIR", "/etc/reviewer/templates")
 	defer func() {
 		os.Unsetenv("DB_HOST")
 		os.Unsetenv("DB_PORT")
@@ -54,7 +96,28 @@ func TestLoad_CustomValues(t *testing.T) {
 		os.Unsetenv("DB_NAME")
 		os.Unsetenv("DB_SSL_MODE")
 		os.Unsetenv("SERVER_PORT")
+		os.Unsetenv("GRPC_PORT")
 		os.Unsetenv("LOG_LEVEL")
+		os.Unsetenv("IDEMPOTENCY_TTL")
+		os.Unsetenv("IDEMPOTENCY_LOCK_TTL")
+		os.Unsetenv("OPENAPI_VALIDATE_REQUESTS")
+		os.Unsetenv("OPENAPI_VALIDATE_RESPONSES")
+		os.Unsetenv("ASSIGNMENT_MODE")
+		os.Unsetenv("REVIEWER_ROTATION_WINDOW")
+		os.Unsetenv("SLA_CHECK_INTERVAL")
+		os.Unsetenv("IDEMPOTENCY_CLEANUP_INTERVAL")
+		os.Unsetenv("DIGEST_CHECK_INTERVAL")
+		os.Unsetenv("DIGEST_PERIOD")
+		os.Unsetenv("DIGEST_WEBHOOK_URL")
+		os.Unsetenv("NOTIFY_TIMEOUT")
+		os.Unsetenv("PR_URL_TEMPLATE")
+		os.Unsetenv("SMTP_HOST")
+		os.Unsetenv("SMTP_PORT")
+		os.Unsetenv("SMTP_USERNAME")
+		os.Unsetenv("SMTP_PASSWORD")
+		os.Unsetenv("SMTP_FROM")
+		os.Unsetenv("SMTP_TLS")
+		os.Unsetenv("EMAIL_TEMPLATES_DIR")
 	}()
 
 	cfg, err := config.Load()
@@ -67,7 +130,100 @@ func TestLoad_CustomValues(t *testing.T) {
 	assert.Equal(t, "customdb", cfg.DBName)
 	assert.Equal(t, "require", cfg.DBSSLMode)
 	assert.Equal(t, "9090", cfg.ServerPort)
+	assert.Equal(t, "9191", cfg.GRPCPort)
 	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, time.Hour, cfg.IdempotencyTTL)
+	assert.Equal(t, 30*time.Second, cfg.IdempotencyLockTTL)
+	assert.False(t, cfg.OpenAPIValidateRequests)
+	assert.True(t, cfg.OpenAPIValidateResponses)
+	assert.Equal(t, domain.AssignmentModeWorkingHours, cfg.AssignmentMode)
+	assert.Equal(t, 168*time.Hour, cfg.ReviewerRotationWindow)
+	assert.Zero(t, cfg.SLACheckInterval)
+	assert.Equal(t, 30*time.Minute, cfg.IdempotencyCleanupInterval)
+	assert.Equal(t, 15*time.Minute, cfg.DigestCheckInterval)
+	assert.Equal(t, 12*time.Hour, cfg.DigestPeriod)
+	assert.Equal(t, "https://hooks.example.com/digest", cfg.DigestWebhookURL)
+	assert.Equal(t, 3*time.Second, cfg.NotifyTimeout)
+	assert.Equal(t, "https://git.example.com/pulls/{id}", cfg.PRURLTemplate)
+	assert.Equal(t, "smtp.example.com", cfg.SMTPHost)
+	assert.Equal(t, 465, cfg.SMTPPort)
+	assert.Equal(t, "bot", cfg.SMTPUsername)
+	assert.Equal(t, "secret", cfg.SMTPPassword)
+	assert.Equal(t, "Reviewer Bot <bot@example.com>", cfg.SMTPFrom)
+	assert.Equal(t, notify.SMTPTLSImplicit, cfg.SMTPTLS)
+	assert.Equal(t, "/etc/reviewer/templates", cfg.EmailTemplatesDir)
+}
+
+func TestLoad_InvalidAssignmentMode(t *testing.T) {
+	os.Setenv("DB_HOST", "localhost")
+	os.Setenv("DB_USER", "testuser")
+	os.Setenv("DB_PASSWORD", "testpass")
+	os.Setenv("DB_NAME", "testdb")
+	os.Setenv("ASSIGNMENT_MODE", "round_robin")
+	defer func() {
+		os.Unsetenv("DB_HOST")
+		os.Unsetenv("DB_USER")
+		os.Unsetenv("DB_PASSWORD")
+		os.Unsetenv("DB_NAME")
+		os.Unsetenv("ASSIGNMENT_MODE")
+	}()
+
+	_, err := config.Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_InvalidDigestWebhookURL(t *testing.T) {
+	os.Setenv("DB_HOST", "localhost")
+	os.Setenv("DB_USER", "testuser")
+	os.Setenv("DB_PASSWORD", "testpass")
+	os.Setenv("DB_NAME", "testdb")
+	os.Setenv("DIGEST_WEBHOOK_URL", "hooks.example.com/digest")
+	defer func() {
+		os.Unsetenv("DB_HOST")
+		os.Unsetenv("DB_USER")
+		os.Unsetenv("DB_PASSWORD")
+		os.Unsetenv("DB_NAME")
+		os.Unsetenv("DIGEST_WEBHOOK_URL")
+	}()
+
+	_, err := config.Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_PRURLTemplateWithoutPlaceholder(t *testing.T) {
+	os.Setenv("DB_HOST", "localhost")
+	os.Setenv("DB_USER", "testuser")
+	os.Setenv("DB_PASSWORD", "testpass")
+	os.Setenv("DB_NAME", "testdb")
+	os.Setenv("PR_URL_TEMPLATE", "https://git.example.com/pulls")
+	defer func() {
+		os.Unsetenv("DB_HOST")
+		os.Unsetenv("DB_USER")
+		os.Unsetenv("DB_PASSWORD")
+		os.Unsetenv("DB_NAME")
+		os.Unsetenv("PR_URL_TEMPLATE")
+	}()
+
+	_, err := config.Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_SMTPWithoutSender(t *testing.T) {
+	os.Setenv("DB_HOST", "localhost")
+	os.Setenv("DB_USER", "testuser")
+	os.Setenv("DB_PASSWORD", "testpass")
+	os.Setenv("DB_NAME", "testdb")
+	os.Setenv("SMTP_HOST", "smtp.example.com")
+	defer func() {
+		os.Unsetenv("DB_HOST")
+		os.Unsetenv("DB_USER")
+		os.Unsetenv("DB_PASSWORD")
+		os.Unsetenv("DB_NAME")
+		os.Unsetenv("SMTP_HOST")
+	}()
+
+	_, err := config.Load()
+	assert.Error(t, err)
 }
 
 func TestLoad_MissingRequired(t *testing.T) {
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Absence - период отсутствия пользователя [StartsAt, EndsAt)
+type Absence struct {
+	ID        int64     `json:"absence_id"`
+	UserID    string    `json:"user_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// Covers проверяет, попадает ли момент t в период отсутствия
+func (a *Absence) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
@@ -0,0 +1,164 @@
+package domain
+
+// ExclusionReason - почему участник команды не рассматривался как кандидат в ревьюеры
+type ExclusionReason string
+
+const (
+	ExclusionAuthor          ExclusionReason = "author"           // автор PR
+	ExclusionAlreadyAssigned ExclusionReason = "already_assigned" // уже назначен на PR
+	ExclusionForbidden       ExclusionReason = "forbidden"        // правило forbid для автора
+	ExclusionInactive        ExclusionReason = "inactive"
+	ExclusionAbsent          ExclusionReason = "absent"      // сейчас отсутствует
+	ExclusionAtCapacity      ExclusionReason = "at_capacity" // достиг лимита открытых ревью
+	ExclusionNotSenior       ExclusionReason = "not_senior"  // правило команды требует senior-ревьюера
+)
+
+// SelectionReason - правило или стратегия, по которой выбран ревьюер
+type SelectionReason string
+
+const (
+	SelectionPreferRule   SelectionReason = "prefer_rule" // правило prefer для автора
+	SelectionSeniorRule   SelectionReason = "senior_rule" // min_senior_reviewers команды
+	SelectionRandom       SelectionReason = "weighted_random"
+	SelectionWorkingHours SelectionReason = "working_hours"
+	SelectionRequested    SelectionReason = "requested" // замена указана в запросе на переназначение
+)
+
+// ShortfallReason - почему на новый PR назначено меньше MaxReviewersPerPR ревьюеров
+type ShortfallReason string
+
+const (
+	ShortfallNoTeam       ShortfallReason = "no_team"       // автор не состоит в командах
+	ShortfallNoCandidates ShortfallReason = "no_candidates" // в команде не хватает подходящих участников
+	ShortfallAtCapacity   ShortfallReason = "at_capacity"   // остальные участники достигли лимита открытых ревью
+)
+
+// PoolMember - участник команды PR с состоянием, от которого зависит, можно ли назначить его ревьюером
+type PoolMember struct {
+	User        *User
+	OpenReviews int
+	IsAbsent    bool
+}
+
+// Exclusion - участник команды, исключённый из кандидатов
+type Exclusion struct {
+	UserID string          `json:"user_id"`
+	Reason ExclusionReason `json:"reason"`
+}
+
+// ReviewerChoice - выбранный ревьюер и причина выбора
+type ReviewerChoice struct {
+	UserID string          `json:"user_id"`
+	Reason SelectionReason `json:"reason"`
+	// RecentReviews - сколько недавних PR автора ревьюер уже ревьюит (при включённой ротации)
+	RecentReviews int `json:"recent_reviews,omitempty"`
+}
+
+// AssignmentExplanation - почему на PR назначены именно эти ревьюеры
+type AssignmentExplanation struct {
+	TeamName   string            `json:"team_name,omitempty"`
+	Strategy   AssignmentMode    `json:"strategy"`
+	PoolSize   int               `json:"pool_size"`  // участников команды PR
+	Candidates int               `json:"candidates"` // осталось после исключений
+	Excluded   []*Exclusion      `json:"excluded"`
+	Selected   []*ReviewerChoice `json:"selected"`
+	// Родительская команда, из которой выбирались ревьюеры, потому что в команде PR подходящих нет.
+	// PoolSize и Candidates тогда относятся к ней, а Excluded содержит исключённых в обеих командах
+	FallbackTeam string `json:"fallback_team,omitempty"`
+	// Почему ревьюеров меньше MaxReviewersPerPR; пусто - назначено сколько положено
+	Shortfall ShortfallReason `json:"shortfall,omitempty"`
+}
+
+// AssignmentPreview - ревьюеры, которые были бы назначены на новый PR автора
+type AssignmentPreview struct {
+	AuthorID          string                 `json:"author_id"`
+	ProposedReviewers []string               `json:"proposed_reviewers"`
+	EligiblePool      []*User                `json:"eligible_pool"` // все подходящие кандидаты в порядке приоритета
+	Assignment        *AssignmentExplanation `json:"assignment"`
+}
+
+// FilterPool отбирает кандидатов из участников команды. excluded задаёт причины исключения
+// для конкретных пользователей (автор, уже назначенные, запрещённые правилами); остальные
+// исключаются, если неактивны, отсутствуют или достигли лимита открытых ревью
+func FilterPool(pool []*PoolMember, excluded map[string]ExclusionReason) ([]*User, []*Exclusion) {
+	candidates := make([]*User, 0, len(pool))
+	exclusions := make([]*Exclusion, 0)
+
+	for _, m := range pool {
+		reason, ok := excluded[m.User.ID]
+		switch {
+		case ok:
+		case !m.User.IsActive:
+			reason = ExclusionInactive
+		case m.IsAbsent:
+			reason = ExclusionAbsent
+		case m.User.MaxOpenReviews != nil && m.OpenReviews >= *m.User.MaxOpenReviews:
+			reason = ExclusionAtCapacity
+		default:
+			candidates = append(candidates, m.User)
+			continue
+		}
+		exclusions = append(exclusions, &Exclusion{UserID: m.User.ID, Reason: reason})
+	}
+
+	return candidates, exclusions
+}
+
+// ExplainShortfall определяет, почему на новый PR назначено assigned ревьюеров вместо MaxReviewersPerPR.
+// Если кому-то из команды помешал лимит открытых ревью, причиной считается лимит
+func ExplainShortfall(hasTeam bool, assigned int, exclusions []*Exclusion) ShortfallReason {
+	switch {
+	case assigned >= MaxReviewersPerPR:
+		return ""
+	case !hasTeam:
+		return ShortfallNoTeam
+	case CountExclusions(exclusions, ExclusionAtCapacity) > 0:
+		return ShortfallAtCapacity
+	default:
+		return ShortfallNoCandidates
+	}
+}
+
+// CountExclusions возвращает число исключений с указанной причиной
+func CountExclusions(exclusions []*Exclusion, reason ExclusionReason) int {
+	n := 0
+	for _, e := range exclusions {
+		if e.Reason == reason {
+			n++
+		}
+	}
+	return n
+}
+
+// ExplainPicks определяет причину выбора каждого ревьюера из picked.
+// ranked - кандидаты в порядке приоритета, count - сколько мест было:
+// кандидат, попавший в picked не из первых count, выбран правилом о senior-ревьюерах
+func ExplainPicks(picked, ranked []*User, count int, preferred []string, mode AssignmentMode, recent map[string]int) []*ReviewerChoice {
+	isPreferred := make(map[string]bool, len(preferred))
+	for _, id := range preferred {
+		isPreferred[id] = true
+	}
+	position := make(map[string]int, len(ranked))
+	for i, u := range ranked {
+		position[u.ID] = i
+	}
+
+	strategy := SelectionRandom
+	if mode == AssignmentModeWorkingHours {
+		strategy = SelectionWorkingHours
+	}
+
+	choices := make([]*ReviewerChoice, len(picked))
+	for i, u := range picked {
+		reason := strategy
+		switch {
+		case isPreferred[u.ID]:
+			reason = SelectionPreferRule
+		case position[u.ID] >= count:
+			reason = SelectionSeniorRule
+		}
+		choices[i] = &ReviewerChoice{UserID: u.ID, Reason: reason, RecentReviews: recent[u.ID]}
+	}
+
+	return choices
+}
@@ -1,9 +1,13 @@
 package domain_test
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
 )
@@ -34,3 +38,324 @@ func TestUser_Methods(t *testing.T) {
 	assert.Equal(t, "Team 1", user.TeamName)
 	assert.True(t, user.IsActive)
 }
+
+func TestUser_EffectiveReviewWeight(t *testing.T) {
+	assert.Equal(t, domain.DefaultReviewWeight, (&domain.User{}).EffectiveReviewWeight())
+	assert.Equal(t, 2.5, (&domain.User{ReviewWeight: 2.5}).EffectiveReviewWeight())
+}
+
+func TestUserSettings_Validate(t *testing.T) {
+	weight := func(v float64) *float64 { return &v }
+
+	assert.True(t, (&domain.UserSettings{}).IsEmpty())
+	assert.NoError(t, (&domain.UserSettings{ReviewWeight: weight(0.5)}).Validate())
+	assert.NoError(t, (&domain.UserSettings{ReviewWeight: weight(domain.MaxReviewWeight)}).Validate())
+	assert.Error(t, (&domain.UserSettings{ReviewWeight: weight(0)}).Validate())
+	assert.Error(t, (&domain.UserSettings{ReviewWeight: weight(-1)}).Validate())
+	assert.Error(t, (&domain.UserSettings{ReviewWeight: weight(domain.MaxReviewWeight + 1)}).Validate())
+
+	email := func(v string) *string { return &v }
+	assert.NoError(t, (&domain.UserSettings{Email: email("bob@example.com")}).Validate())
+	assert.NoError(t, (&domain.UserSettings{Email: email("")}).Validate()) // удаление адреса
+	assert.Error(t, (&domain.UserSettings{Email: email("bob")}).Validate())
+	assert.Error(t, (&domain.UserSettings{Email: email("Bob <bob@example.com>")}).Validate())
+
+	assert.NoError(t, (&domain.UserSettings{ChatHandle: email("<@U024BE7LH>")}).Validate())
+	assert.NoError(t, (&domain.UserSettings{ChatHandle: email("@bob.smith")}).Validate())
+	assert.NoError(t, (&domain.UserSettings{ChatHandle: email("")}).Validate()) // удаление упоминания
+	assert.Error(t, (&domain.UserSettings{ChatHandle: email("@bob smith")}).Validate())
+	assert.Error(t, (&domain.UserSettings{ChatHandle: email("<!channel>")}).Validate())
+	assert.Error(t, (&domain.UserSettings{ChatHandle: email("@here")}).Validate())
+	assert.Error(t, (&domain.UserSettings{ChatHandle: email("<@U1|x>")}).Validate())
+	assert.Error(t, (&domain.UserSettings{ChatHandle: email("bob")}).Validate())
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, domain.ValidateWebhookURL("https://hooks.slack.com/services/T0/B0/x"))
+	assert.NoError(t, domain.ValidateWebhookURL("http://mattermost.local/hooks/abc"))
+	assert.Error(t, domain.ValidateWebhookURL("hooks.slack.com/services/T0/B0/x"))
+	assert.Error(t, domain.ValidateWebhookURL("ftp://example.com/hook"))
+}
+
+func TestIdempotencyRecord_IsCompleted(t *testing.T) {
+	pending := &domain.IdempotencyRecord{Key: "k1", Scope: "POST /pullRequest/create"}
+	assert.False(t, pending.IsCompleted())
+
+	completed := &domain.IdempotencyRecord{Key: "k1", Scope: "POST /pullRequest/create", StatusCode: 201}
+	assert.True(t, completed.IsCompleted())
+}
+
+func TestTransferPolicy_IsValid(t *testing.T) {
+	assert.True(t, domain.TransferPolicyReject.IsValid())
+	assert.True(t, domain.TransferPolicyMove.IsValid())
+	assert.True(t, domain.TransferPolicySkip.IsValid())
+	assert.True(t, domain.TransferPolicyJoin.IsValid())
+	assert.False(t, domain.TransferPolicy("").IsValid())
+	assert.False(t, domain.TransferPolicy("merge").IsValid())
+}
+
+func TestTeamTree(t *testing.T) {
+	tree := domain.NewTeamTree([]*domain.TeamNode{
+		{ID: 1, Name: "org"},
+		{ID: 2, ParentID: 1, Name: "backend"},
+		{ID: 3, ParentID: 2, Name: "payments"},
+		{ID: 4, ParentID: 1, Name: "frontend"},
+		{ID: 5, ParentID: 42, Name: "orphan"},
+	})
+
+	require.Len(t, tree.Roots, 2)
+	assert.Equal(t, "org", tree.Roots[0].Name)
+	assert.Equal(t, "orphan", tree.Roots[1].Name)
+
+	org := tree.Node(1)
+	require.Len(t, org.Children, 2)
+	assert.Equal(t, "backend", org.Children[0].Name)
+	assert.Equal(t, "frontend", org.Children[1].Name)
+	assert.Empty(t, tree.Node(3).Children)
+
+	ancestors := tree.Ancestors(3)
+	require.Len(t, ancestors, 2)
+	assert.Equal(t, "org", ancestors[0].Name)
+	assert.Equal(t, "backend", ancestors[1].Name)
+
+	assert.Empty(t, tree.Ancestors(1))
+	assert.Empty(t, tree.Ancestors(5))
+	assert.Nil(t, tree.Node(100))
+}
+
+func TestAbsence_Covers(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	absence := &domain.Absence{StartsAt: start, EndsAt: start.Add(14 * 24 * time.Hour)}
+
+	assert.False(t, absence.Covers(start.Add(-time.Second)))
+	assert.True(t, absence.Covers(start))
+	assert.True(t, absence.Covers(start.Add(7*24*time.Hour)))
+	assert.False(t, absence.Covers(absence.EndsAt))
+}
+
+func TestWorkSchedule_Validate(t *testing.T) {
+	valid := &domain.WorkSchedule{Timezone: "Europe/Berlin", Start: "09:00", End: "18:00", Days: []int{1, 2, 3, 4, 5}}
+	assert.NoError(t, valid.Validate())
+
+	invalid := []*domain.WorkSchedule{
+		{Timezone: "Mars/Olympus", Start: "09:00", End: "18:00", Days: []int{1}},
+		{Timezone: "UTC", Start: "9am", End: "18:00", Days: []int{1}},
+		{Timezone: "UTC", Start: "09:00", End: "09:00", Days: []int{1}},
+		{Timezone: "UTC", Start: "09:00", End: "18:00", Days: []int{0}},
+		{Timezone: "UTC", Start: "09:00", End: "18:00", Days: []int{1, 1}},
+		{Timezone: "UTC", Start: "09:00", End: "18:00"},
+	}
+	for _, s := range invalid {
+		assert.Error(t, s.Validate(), "%+v", s)
+	}
+}
+
+func TestWorkSchedule_UntilWorking(t *testing.T) {
+	schedule := &domain.WorkSchedule{Timezone: "Asia/Tokyo", Start: "09:00", End: "18:00", Days: []int{1, 2, 3, 4, 5}}
+
+	// Среда, 10:00 в Токио
+	wednesday := time.Date(2025, 7, 2, 1, 0, 0, 0, time.UTC)
+	assert.True(t, schedule.IsWorkingAt(wednesday))
+
+	// Среда, 20:00 в Токио - до начала рабочего дня 13 часов
+	evening := time.Date(2025, 7, 2, 11, 0, 0, 0, time.UTC)
+	assert.Equal(t, 13*time.Hour, schedule.UntilWorking(evening))
+
+	// Суббота, 10:00 в Токио - ближайшее рабочее время в понедельник
+	saturday := time.Date(2025, 7, 5, 1, 0, 0, 0, time.UTC)
+	assert.Equal(t, 47*time.Hour, schedule.UntilWorking(saturday))
+}
+
+func TestWorkSchedule_Overnight(t *testing.T) {
+	schedule := &domain.WorkSchedule{Timezone: "UTC", Start: "22:00", End: "06:00", Days: []int{1}}
+
+	// Вторник, 03:00 - смена началась в понедельник
+	assert.True(t, schedule.IsWorkingAt(time.Date(2025, 7, 1, 3, 0, 0, 0, time.UTC)))
+	// Вторник, 07:00 - следующая смена в понедельник
+	assert.False(t, schedule.IsWorkingAt(time.Date(2025, 7, 1, 7, 0, 0, 0, time.UTC)))
+}
+
+func TestPickReviewers(t *testing.T) {
+	ranked := []*domain.User{
+		{ID: "u1", Seniority: domain.SeniorityJunior},
+		{ID: "u2", Seniority: domain.SeniorityMiddle},
+		{ID: "u3", Seniority: domain.SenioritySenior},
+		{ID: "u4", Seniority: domain.SenioritySenior},
+	}
+	ids := func(users []*domain.User) []string {
+		result := make([]string, len(users))
+		for i, u := range users {
+			result[i] = u.ID
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"u1", "u2"}, ids(domain.PickReviewers(ranked, 2, 0)))
+	assert.Equal(t, []string{"u1", "u3"}, ids(domain.PickReviewers(ranked, 2, 1)))
+	assert.Equal(t, []string{"u3", "u4"}, ids(domain.PickReviewers(ranked, 2, 2)))
+	assert.Equal(t, []string{"u3"}, ids(domain.PickReviewers(ranked, 1, 2)))
+
+	// Senior-кандидатов не хватает - правило выполняется частично
+	assert.Equal(t, []string{"u1", "u3"}, ids(domain.PickReviewers(ranked[:3], 2, 2)))
+	assert.Empty(t, domain.PickReviewers(nil, 2, 1))
+	assert.Equal(t, 2, domain.CountSeniors(ranked))
+}
+
+func TestReviewerRules(t *testing.T) {
+	assert.True(t, domain.ReviewerRulePrefer.IsValid())
+	assert.True(t, domain.ReviewerRuleForbid.IsValid())
+	assert.False(t, domain.ReviewerRuleKind("ignore").IsValid())
+
+	preferred, forbidden := domain.SplitReviewerRules([]*domain.ReviewerRule{
+		{ReviewerID: "u2", Kind: domain.ReviewerRulePrefer},
+		{ReviewerID: "u3", Kind: domain.ReviewerRuleForbid},
+	})
+	assert.Equal(t, []string{"u2"}, preferred)
+	assert.Equal(t, []string{"u3"}, forbidden)
+
+	ranked := []*domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u4"}, {ID: "u5"}}
+	reordered := domain.PreferFirst(ranked, []string{"u5", "u2"})
+	require.Len(t, reordered, 4)
+	assert.Equal(t, "u2", reordered[0].ID)
+	assert.Equal(t, "u5", reordered[1].ID)
+	assert.Equal(t, "u1", reordered[2].ID)
+	assert.Equal(t, "u4", reordered[3].ID)
+}
+
+func TestFilterPool(t *testing.T) {
+	limit := 1
+	pool := []*domain.PoolMember{
+		{User: &domain.User{ID: "u1", IsActive: true}},
+		{User: &domain.User{ID: "u2", IsActive: false}},
+		{User: &domain.User{ID: "u3", IsActive: true}, IsAbsent: true},
+		{User: &domain.User{ID: "u4", IsActive: true, MaxOpenReviews: &limit}, OpenReviews: 1},
+		{User: &domain.User{ID: "u5", IsActive: true, MaxOpenReviews: &limit}},
+		{User: &domain.User{ID: "u6", IsActive: false}},
+	}
+
+	candidates, exclusions := domain.FilterPool(pool, map[string]domain.ExclusionReason{
+		"u1": domain.ExclusionAuthor,
+		"u6": domain.ExclusionForbidden,
+	})
+
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "u5", candidates[0].ID)
+	assert.Equal(t, []*domain.Exclusion{
+		{UserID: "u1", Reason: domain.ExclusionAuthor},
+		{UserID: "u2", Reason: domain.ExclusionInactive},
+		{UserID: "u3", Reason: domain.ExclusionAbsent},
+		{UserID: "u4", Reason: domain.ExclusionAtCapacity},
+		{UserID: "u6", Reason: domain.ExclusionForbidden},
+	}, exclusions)
+	assert.Equal(t, 1, domain.CountExclusions(exclusions, domain.ExclusionAtCapacity))
+}
+
+func TestExplainShortfall(t *testing.T) {
+	atCapacity := []*domain.Exclusion{{UserID: "u2", Reason: domain.ExclusionAtCapacity}}
+
+	assert.Empty(t, domain.ExplainShortfall(true, domain.MaxReviewersPerPR, atCapacity))
+	assert.Equal(t, domain.ShortfallNoTeam, domain.ExplainShortfall(false, 0, nil))
+	assert.Equal(t, domain.ShortfallAtCapacity, domain.ExplainShortfall(true, 0, atCapacity))
+	assert.Equal(t, domain.ShortfallNoCandidates, domain.ExplainShortfall(true, 1, []*domain.Exclusion{
+		{UserID: "u1", Reason: domain.ExclusionAuthor},
+	}))
+}
+
+func TestExplainPicks(t *testing.T) {
+	ranked := []*domain.User{
+		{ID: "u1"},
+		{ID: "u2"},
+		{ID: "u3", Seniority: domain.SenioritySenior},
+	}
+	picked := domain.PickReviewers(ranked, 2, 1)
+
+	choices := domain.ExplainPicks(picked, ranked, 2, []string{"u1"}, domain.AssignmentModeRandom, map[string]int{"u3": 2})
+	require.Len(t, choices, 2)
+	assert.Equal(t, &domain.ReviewerChoice{UserID: "u1", Reason: domain.SelectionPreferRule}, choices[0])
+	assert.Equal(t, &domain.ReviewerChoice{UserID: "u3", Reason: domain.SelectionSeniorRule, RecentReviews: 2}, choices[1])
+
+	choices = domain.ExplainPicks(ranked[:1], ranked, 1, nil, domain.AssignmentModeWorkingHours, nil)
+	assert.Equal(t, domain.SelectionWorkingHours, choices[0].Reason)
+}
+
+func TestReviewSLA(t *testing.T) {
+	assert.NoError(t, (&domain.ReviewSLA{Hours: 24, Action: domain.SLAActionReassign}).Validate())
+	assert.Error(t, (&domain.ReviewSLA{Hours: 0, Action: domain.SLAActionReassign}).Validate())
+	assert.Error(t, (&domain.ReviewSLA{Hours: domain.MaxReviewSLAHours + 1, Action: domain.SLAActionAddReviewer}).Validate())
+	assert.Error(t, (&domain.ReviewSLA{Hours: 24, Action: "escalate"}).Validate())
+
+	sla := &domain.ReviewSLA{Hours: 24, Action: domain.SLAActionReassign}
+
+	// Среда, 10:00 - срок в четверг, 10:00
+	wednesday := time.Date(2025, 7, 2, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, wednesday.Add(24*time.Hour), sla.Deadline(wednesday))
+
+	// Пятница, 10:00 - выходные не считаются, срок в понедельник, 10:00
+	friday := time.Date(2025, 7, 4, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 7, 7, 10, 0, 0, 0, time.UTC), sla.Deadline(friday))
+
+	// Суббота - отсчёт начинается с понедельника
+	saturday := time.Date(2025, 7, 5, 15, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC), sla.Deadline(saturday))
+
+	// Время назначения в другом часовом поясе: выходные определяются по UTC
+	short := &domain.ReviewSLA{Hours: 8, Action: domain.SLAActionReassign}
+
+	// Пятница, 23:30 MSK - это пятница, 20:30 UTC: 3.5 часа в пятницу, остальное в понедельник
+	moscow := time.FixedZone("MSK", 3*60*60)
+	fridayNight := time.Date(2025, 7, 4, 23, 30, 0, 0, moscow)
+	assert.Equal(t, time.Date(2025, 7, 7, 4, 30, 0, 0, time.UTC), short.Deadline(fridayNight))
+
+	// Воскресенье, 20:00 PDT - это уже понедельник, 03:00 UTC
+	pacific := time.FixedZone("PDT", -7*60*60)
+	sundayEvening := time.Date(2025, 7, 6, 20, 0, 0, 0, pacific)
+	assert.Equal(t, time.Date(2025, 7, 7, 11, 0, 0, 0, time.UTC), short.Deadline(sundayEvening))
+	assert.Equal(t, short.Deadline(sundayEvening.UTC()), short.Deadline(sundayEvening))
+}
+
+func TestJobRun_Finish(t *testing.T) {
+	start := time.Date(2025, 7, 2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Second)
+
+	run := &domain.JobRun{JobName: "review_sla", Status: domain.JobRunRunning, StartedAt: start}
+	run.Finish(3, nil, end)
+	assert.Equal(t, domain.JobRunSucceeded, run.Status)
+	assert.Equal(t, 3, run.Processed)
+	assert.Equal(t, &end, run.FinishedAt)
+	assert.Empty(t, run.Error)
+
+	failed := &domain.JobRun{JobName: "review_sla", Status: domain.JobRunRunning, StartedAt: start}
+	failed.Finish(1, errors.New("connection refused"), end)
+	assert.Equal(t, domain.JobRunFailed, failed.Status)
+	assert.Equal(t, 1, failed.Processed)
+	assert.Equal(t, "connection refused", failed.Error)
+}
+
+func TestQuietHours(t *testing.T) {
+	assert.NoError(t, (&domain.QuietHours{Timezone: "Europe/Berlin", Start: "22:00", End: "08:00"}).Validate())
+	assert.Error(t, (&domain.QuietHours{Timezone: "Mars/Olympus", Start: "22:00", End: "08:00"}).Validate())
+	assert.Error(t, (&domain.QuietHours{Timezone: "UTC", Start: "22:00", End: "22:00"}).Validate())
+	assert.Error(t, (&domain.QuietHours{Timezone: "UTC", Start: "10pm", End: "08:00"}).Validate())
+
+	// Через полночь: 22:00-08:00 по Берлину (UTC+2 летом)
+	night := &domain.QuietHours{Timezone: "Europe/Berlin", Start: "22:00", End: "08:00"}
+	assert.True(t, night.Contains(time.Date(2025, 7, 2, 21, 0, 0, 0, time.UTC)))  // 23:00
+	assert.True(t, night.Contains(time.Date(2025, 7, 2, 5, 59, 0, 0, time.UTC)))  // 07:59
+	assert.False(t, night.Contains(time.Date(2025, 7, 2, 6, 0, 0, 0, time.UTC)))  // 08:00
+	assert.False(t, night.Contains(time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC))) // 14:00
+
+	lunch := &domain.QuietHours{Timezone: "UTC", Start: "12:00", End: "13:00"}
+	assert.True(t, lunch.Contains(time.Date(2025, 7, 2, 12, 30, 0, 0, time.UTC)))
+	assert.False(t, lunch.Contains(time.Date(2025, 7, 2, 13, 0, 0, 0, time.UTC)))
+}
+
+func TestUser_MarshalJSONHidesEmail(t *testing.T) {
+	data, err := json.Marshal(&domain.User{ID: "u1", Username: "Alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "alice@example.com")
+	assert.Contains(t, string(data), `"email_configured":true`)
+
+	data, err = json.Marshal(domain.User{ID: "u2"})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"email_configured":false`)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord - сохранённый результат запроса с Idempotency-Key
+type IdempotencyRecord struct {
+	Key          string
+	Scope        string // метод и путь запроса
+	RequestHash  string
+	StatusCode   int // 0, пока запрос ещё обрабатывается
+	ResponseBody []byte
+	ETag         string // заголовок ETag ответа, пусто - ответ без ETag
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	LockedUntil  time.Time // до этого момента незавершённый запрос держит ключ
+}
+
+// IsCompleted проверяет, сохранён ли уже ответ на запрос
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
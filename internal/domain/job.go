@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// JobTrigger - причина запуска фоновой задачи
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule" // по расписанию
+	JobTriggerManual   JobTrigger = "manual"   // через /admin/triggerJob
+)
+
+// JobRunStatus - состояние запуска фоновой задачи
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// JobRun - запуск фоновой задачи
+type JobRun struct {
+	ID         int64        `json:"run_id"`
+	JobName    string       `json:"job_name"`
+	Trigger    JobTrigger   `json:"trigger"`
+	Status     JobRunStatus `json:"status"`
+	Processed  int          `json:"processed"` // сколько объектов обработал запуск
+	Error      string       `json:"error,omitempty"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at"`
+}
+
+// Finish завершает запуск с результатом задачи
+func (r *JobRun) Finish(processed int, err error, at time.Time) {
+	r.Processed = processed
+	r.FinishedAt = &at
+	r.Status = JobRunSucceeded
+	if err != nil {
+		r.Status = JobRunFailed
+		r.Error = err.Error()
+	}
+}
+
+// JobInfo - зарегистрированная фоновая задача и её последний запуск
+type JobInfo struct {
+	Name            string  `json:"job_name"`
+	Description     string  `json:"description"`
+	IntervalSeconds int64   `json:"interval_seconds"` // 0 - только ручной запуск
+	LastRun         *JobRun `json:"last_run"`
+}
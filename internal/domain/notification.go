@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// QuietHours - время суток, когда пользователю не отправляются уведомления.
+// Если End не позже Start, интервал переходит через полночь
+type QuietHours struct {
+	Timezone string `json:"timezone"` // IANA, например "Europe/Moscow"
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+}
+
+// Validate проверяет часовой пояс и формат времени
+func (q *QuietHours) Validate() error {
+	if _, err := time.LoadLocation(q.Timezone); err != nil || q.Timezone == "" {
+		return fmt.Errorf("unknown timezone: %q", q.Timezone)
+	}
+
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+
+	return nil
+}
+
+// Contains проверяет, попадает ли момент t в тихие часы.
+// Для некорректного интервала возвращает false, чтобы уведомления не терялись
+func (q *QuietHours) Contains(t time.Time) bool {
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return false
+	}
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	if start < end {
+		return clock >= start && clock < end
+	}
+	return clock >= start || clock < end
+}
+
+// ReviewDigest - сводка открытых PR, ожидающих ревью пользователя
+type ReviewDigest struct {
+	User         *User
+	PullRequests []*PullRequest
+	GeneratedAt  time.Time
+}
+
+// PREventKind - событие PR, о котором уведомляются пользователи
+type PREventKind string
+
+const (
+	PREventAssigned   PREventKind = "assigned"   // на PR назначены ревьюеры
+	PREventReassigned PREventKind = "reassigned" // ревьюер заменён другим
+	PREventMerged     PREventKind = "merged"     // PR смержен
+)
+
+// MaxChatHandleLength - максимальная длина упоминания пользователя в чате
+const MaxChatHandleLength = 100
+
+// chatHandlePattern - упоминание пользователя в Mattermost (@name) или Slack (<@ID>)
+var chatHandlePattern = regexp.MustCompile(`^(@[A-Za-z0-9._-]+|<@[A-Z0-9]+>)$`)
+
+// ValidChatHandle проверяет, что handle - упоминание одного пользователя в Mattermost (@name) или Slack (<@ID>).
+// Групповые упоминания Mattermost (@all, @channel, @here) не допускаются
+func ValidChatHandle(handle string) bool {
+	if len(handle) > MaxChatHandleLength || !chatHandlePattern.MatchString(handle) {
+		return false
+	}
+	switch strings.ToLower(handle) {
+	case "@all", "@channel", "@here":
+		return false
+	}
+	return true
+}
+
+// PREvent - событие PR для уведомлений
+type PREvent struct {
+	Kind       PREventKind
+	PR         *PullRequest
+	Team       *Team // команда PR, nil - PR без команды
+	Author     *User
+	Reviewers  []*User // ревьюеры, назначенные этим событием; для PREventMerged - все ревьюеры PR
+	Replaced   *User   // заменённый ревьюер (для PREventReassigned)
+	OccurredAt time.Time
+}
+
+// ValidateWebhookURL проверяет, что URL вебхука абсолютный и использует http или https
+func ValidateWebhookURL(raw string) error {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("expected http(s) URL, got %q", raw)
+	}
+	return nil
+}
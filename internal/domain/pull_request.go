@@ -7,14 +7,23 @@ const (
 	StatusMerged = "MERGED"
 )
 
+// Действия в журнале ручных изменений ревьюеров PR
+const (
+	ReviewerChangeAdded   = "added"
+	ReviewerChangeRemoved = "removed"
+)
+
 type PullRequest struct {
 	ID                string     `json:"pull_request_id"`
 	Name              string     `json:"pull_request_name"`
 	AuthorID          string     `json:"author_id"`
+	TeamID            int        `json:"-"`                   // internal use only; 0 - команда PR удалена
+	TeamName          string     `json:"team_name,omitempty"` // команда, из которой назначаются ревьюеры
 	Status            string     `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	CreatedAt         time.Time  `json:"created_at,omitempty"`
 	MergedAt          *time.Time `json:"merged_at,omitempty"` // nullable
+	Version           int        `json:"version"`
 }
 
 // IsOpen проверяет, открыт ли PR
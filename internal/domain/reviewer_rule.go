@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// ReviewerRuleKind - тип правила для пары автор-ревьюер
+type ReviewerRuleKind string
+
+const (
+	// ReviewerRulePrefer - ревьюер назначается автору в первую очередь
+	ReviewerRulePrefer ReviewerRuleKind = "prefer"
+	// ReviewerRuleForbid - ревьюер никогда не назначается автору
+	ReviewerRuleForbid ReviewerRuleKind = "forbid"
+)
+
+// IsValid проверяет, что тип правила известен
+func (k ReviewerRuleKind) IsValid() bool {
+	return k == ReviewerRulePrefer || k == ReviewerRuleForbid
+}
+
+// ReviewerRule - правило назначения ревьюера автору PR в рамках команды
+type ReviewerRule struct {
+	TeamID     int              `json:"-"`
+	TeamName   string           `json:"team_name"`
+	AuthorID   string           `json:"author_id"`
+	ReviewerID string           `json:"reviewer_id"`
+	Kind       ReviewerRuleKind `json:"kind"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// SplitReviewerRules разделяет правила на предпочитаемых и запрещённых ревьюеров
+func SplitReviewerRules(rules []*ReviewerRule) (preferred, forbidden []string) {
+	for _, rule := range rules {
+		switch rule.Kind {
+		case ReviewerRulePrefer:
+			preferred = append(preferred, rule.ReviewerID)
+		case ReviewerRuleForbid:
+			forbidden = append(forbidden, rule.ReviewerID)
+		}
+	}
+	return preferred, forbidden
+}
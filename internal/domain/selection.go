@@ -0,0 +1,82 @@
+package domain
+
+// MaxReviewersPerPR - сколько ревьюеров назначается на PR при создании; больше назначить нельзя
+const MaxReviewersPerPR = 2
+
+// PickReviewers выбирает до count кандидатов из ranked (упорядочены по приоритету).
+// Сначала берутся senior-кандидаты, пока их не станет minSeniors, остальные места заполняются по порядку.
+// Если senior-кандидатов не хватает, правило выполняется частично
+func PickReviewers(ranked []*User, count, minSeniors int) []*User {
+	if count > len(ranked) {
+		count = len(ranked)
+	}
+	if minSeniors > count {
+		minSeniors = count
+	}
+
+	picked := make(map[string]bool, count)
+	seniors := 0
+	for _, u := range ranked {
+		if seniors == minSeniors {
+			break
+		}
+		if u.IsSenior() {
+			picked[u.ID] = true
+			seniors++
+		}
+	}
+
+	for _, u := range ranked {
+		if len(picked) == count {
+			break
+		}
+		picked[u.ID] = true
+	}
+
+	// Сохраняем порядок приоритета
+	result := make([]*User, 0, count)
+	for _, u := range ranked {
+		if picked[u.ID] {
+			result = append(result, u)
+		}
+	}
+
+	return result
+}
+
+// CountSeniors возвращает число senior-пользователей
+func CountSeniors(users []*User) int {
+	n := 0
+	for _, u := range users {
+		if u.IsSenior() {
+			n++
+		}
+	}
+	return n
+}
+
+// PreferFirst переносит предпочитаемых пользователей в начало списка, сохраняя порядок внутри групп
+func PreferFirst(ranked []*User, preferred []string) []*User {
+	if len(preferred) == 0 {
+		return ranked
+	}
+
+	isPreferred := make(map[string]bool, len(preferred))
+	for _, id := range preferred {
+		isPreferred[id] = true
+	}
+
+	result := make([]*User, 0, len(ranked))
+	for _, u := range ranked {
+		if isPreferred[u.ID] {
+			result = append(result, u)
+		}
+	}
+	for _, u := range ranked {
+		if !isPreferred[u.ID] {
+			result = append(result, u)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxReviewSLAHours - максимальный срок ревью в рабочих часах (30 рабочих дней)
+const MaxReviewSLAHours = 720
+
+// SLAAction - что делать с ревью, не выполненным в срок
+type SLAAction string
+
+const (
+	// SLAActionReassign - заменить просрочившего ревьюера
+	SLAActionReassign SLAAction = "reassign"
+	// SLAActionAddReviewer - добавить ещё одного ревьюера, не снимая просрочившего.
+	// Если на PR уже MaxReviewersPerPR ревьюеров, просрочивший ревьюер заменяется
+	SLAActionAddReviewer SLAAction = "add_reviewer"
+)
+
+// IsValid проверяет, что действие известно
+func (a SLAAction) IsValid() bool {
+	switch a {
+	case SLAActionReassign, SLAActionAddReviewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReviewSLA - срок первого ревью команды в рабочих часах.
+// Рабочими считаются все часы с понедельника по пятницу (UTC)
+type ReviewSLA struct {
+	Hours  int       `json:"hours"`
+	Action SLAAction `json:"action"`
+}
+
+// Validate проверяет срок и действие
+func (s *ReviewSLA) Validate() error {
+	if s.Hours < 1 || s.Hours > MaxReviewSLAHours {
+		return fmt.Errorf("hours must be between 1 and %d", MaxReviewSLAHours)
+	}
+	if !s.Action.IsValid() {
+		return fmt.Errorf("unknown action %q", s.Action)
+	}
+	return nil
+}
+
+// Deadline возвращает срок ревью, назначенного в assignedAt
+func (s *ReviewSLA) Deadline(assignedAt time.Time) time.Time {
+	return AddBusinessHours(assignedAt, s.Hours)
+}
+
+// AddBusinessHours прибавляет к start hours часов, не считая суббот и воскресений (UTC)
+func AddBusinessHours(start time.Time, hours int) time.Time {
+	t := start.UTC()
+	remaining := time.Duration(hours) * time.Hour
+
+	for {
+		dayStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		nextDay := dayStart.AddDate(0, 0, 1)
+
+		if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			t = nextDay
+			continue
+		}
+
+		left := nextDay.Sub(t)
+		if remaining <= left {
+			return t.Add(remaining)
+		}
+		remaining -= left
+		t = nextDay
+	}
+}
+
+// OverdueReview - назначение ревьюера, не выполнившего ревью в срок SLA команды PR
+type OverdueReview struct {
+	PullRequestID string
+	ReviewerID    string
+	TeamName      string
+	AssignedAt    time.Time
+	SLA           ReviewSLA
+}
+
+// ReviewEscalation - событие эскалации просроченного ревью
+type ReviewEscalation struct {
+	ID            int64     `json:"escalation_id"`
+	PullRequestID string    `json:"pull_request_id"`
+	ReviewerID    string    `json:"reviewer_id"`
+	Action        SLAAction `json:"action"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"` // пусто - подходящего кандидата не нашлось
+	CreatedAt     time.Time `json:"created_at"`
+}
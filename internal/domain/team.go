@@ -2,9 +2,86 @@ package domain
 
 import "time"
 
+// TransferPolicy определяет, что делать с участниками, которые уже состоят в других командах
+type TransferPolicy string
+
+const (
+	// TransferPolicyReject - отклонить запрос, если кто-то из участников состоит в другой команде
+	TransferPolicyReject TransferPolicy = "reject"
+	// TransferPolicyMove - перевести таких участников в новую команду, исключив из остальных
+	TransferPolicyMove TransferPolicy = "move"
+	// TransferPolicySkip - оставить таких участников в их командах
+	TransferPolicySkip TransferPolicy = "skip"
+	// TransferPolicyJoin - добавить таких участников в команду, сохранив членство в остальных
+	TransferPolicyJoin TransferPolicy = "join"
+)
+
+// IsValid проверяет, что политика известна
+func (p TransferPolicy) IsValid() bool {
+	switch p {
+	case TransferPolicyReject, TransferPolicyMove, TransferPolicySkip, TransferPolicyJoin:
+		return true
+	default:
+		return false
+	}
+}
+
 type Team struct {
-	ID        int       `json:"-"`
-	Name      string    `json:"team_name"`
-	Members   []*User   `json:"members"`
-	CreatedAt time.Time `json:"-"`
+	ID                    int         `json:"-"`
+	Name                  string      `json:"team_name"`
+	ParentID              int         `json:"-"` // 0 - корневая команда
+	ParentName            string      `json:"parent_team_name,omitempty"`
+	Ancestors             []string    `json:"ancestors,omitempty"` // цепочка от корня до родителя
+	Subteams              []*TeamNode `json:"subteams,omitempty"`
+	MinSeniorReviewers    int         `json:"min_senior_reviewers"`    // сколько из назначенных на PR ревьюеров должны быть senior
+	ReviewSLA             *ReviewSLA  `json:"review_sla,omitempty"`    // nil - SLA не задан
+	ChatWebhookURL        string      `json:"-"`                       // вебхук Slack/Mattermost для уведомлений о PR, пусто - не задан. Содержит токен, в API не возвращается
+	ChatWebhookConfigured bool        `json:"chat_webhook_configured"` // задан ли ChatWebhookURL
+	Members               []*User     `json:"members"`
+	CreatedAt             time.Time   `json:"-"`
+}
+
+// MemberTransfer - участник, который состоял в другой команде на момент добавления в команду.
+// Если участник состоял в нескольких командах, на каждую команду приходится отдельная запись
+type MemberTransfer struct {
+	UserID   string `json:"user_id"`
+	FromTeam string `json:"from_team"`
+	// Открытые PR прежней команды, с ревью которых снят перемещённый участник
+	ReleasedReviews []string `json:"released_reviews,omitempty"`
+}
+
+// TeamTransferReport - участники, перемещённые из других команд, пропущенные
+// или добавленные с сохранением членства в других командах
+type TeamTransferReport struct {
+	Moved   []MemberTransfer `json:"moved"`
+	Skipped []MemberTransfer `json:"skipped"`
+	Joined  []MemberTransfer `json:"joined"`
+}
+
+// TeamUpdate - изменения команды: переименование, добавление и удаление участников
+type TeamUpdate struct {
+	Name               string
+	NewName            string // пустая строка - не переименовывать
+	AddMembers         []*User
+	RemoveMembers      []string
+	TransferPolicy     TransferPolicy
+	ParentName         *string // nil - не менять, пустая строка - сделать команду корневой
+	MinSeniorReviewers *int    // nil - не менять
+	ChatWebhookURL     *string // nil - не менять, пустая строка - удалить вебхук
+}
+
+// ReviewAssignment - назначение ревьюера на PR
+type ReviewAssignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// TeamChangeReport - результат изменения или удаления команды.
+// Участники, покинувшие команду, снимаются с ревью открытых PR этой команды
+type TeamChangeReport struct {
+	TeamTransferReport
+	Removed         []string           `json:"removed"`
+	ReleasedReviews []ReviewAssignment `json:"released_reviews"`
+	// Подкоманды удалённой команды, перенесённые к её родителю
+	ReparentedSubteams []string `json:"reparented_subteams,omitempty"`
 }
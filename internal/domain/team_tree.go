@@ -0,0 +1,71 @@
+package domain
+
+// TeamNode - узел дерева команд. Total-счётчики включают все подкоманды
+type TeamNode struct {
+	ID                int         `json:"-"`
+	ParentID          int         `json:"-"` // 0 - корневая команда
+	Name              string      `json:"team_name"`
+	MembersCount      int         `json:"members_count"`
+	TotalMembersCount int         `json:"total_members_count"` // участник нескольких команд считается один раз
+	OpenPRsCount      int         `json:"open_prs_count"`
+	TotalOpenPRsCount int         `json:"total_open_prs_count"`
+	Children          []*TeamNode `json:"children"`
+}
+
+// TeamTree - иерархия команд. Корневых команд (организаций) может быть несколько
+type TeamTree struct {
+	Roots []*TeamNode
+	nodes map[int]*TeamNode
+}
+
+// NewTeamTree строит дерево из плоского списка узлов. Порядок детей совпадает с порядком в списке.
+// Узлы, родитель которых отсутствует в списке, считаются корневыми
+func NewTeamTree(nodes []*TeamNode) *TeamTree {
+	tree := &TeamTree{
+		Roots: []*TeamNode{},
+		nodes: make(map[int]*TeamNode, len(nodes)),
+	}
+
+	for _, n := range nodes {
+		n.Children = []*TeamNode{}
+		tree.nodes[n.ID] = n
+	}
+
+	for _, n := range nodes {
+		parent, ok := tree.nodes[n.ParentID]
+		if n.ParentID == 0 || !ok {
+			tree.Roots = append(tree.Roots, n)
+			continue
+		}
+		parent.Children = append(parent.Children, n)
+	}
+
+	return tree
+}
+
+// Node возвращает узел команды или nil, если команды нет в дереве
+func (t *TeamTree) Node(teamID int) *TeamNode {
+	return t.nodes[teamID]
+}
+
+// Ancestors возвращает предков команды от корня до родителя
+func (t *TeamTree) Ancestors(teamID int) []*TeamNode {
+	var chain []*TeamNode
+
+	node := t.nodes[teamID]
+	// Ограничение по числу узлов защищает от зацикливания на некорректных данных
+	for node != nil && node.ParentID != 0 && len(chain) < len(t.nodes) {
+		parent := t.nodes[node.ParentID]
+		if parent == nil {
+			break
+		}
+		chain = append(chain, parent)
+		node = parent
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+
+	return chain
+}
@@ -1,13 +1,117 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"time"
+)
+
+const (
+	// DefaultReviewWeight - вес пользователя при выборе ревьюеров по умолчанию
+	DefaultReviewWeight = 1.0
+	// MaxReviewWeight - максимальный вес пользователя при выборе ревьюеров
+	MaxReviewWeight = 100.0
+)
+
+// Seniority - уровень пользователя
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "junior"
+	SeniorityMiddle Seniority = "middle"
+	SenioritySenior Seniority = "senior"
+)
+
+// IsValid проверяет, что уровень известен
+func (s Seniority) IsValid() bool {
+	switch s {
+	case SeniorityJunior, SeniorityMiddle, SenioritySenior:
+		return true
+	default:
+		return false
+	}
+}
 
 type User struct {
-	ID        string    `json:"id"`
-	Username  string    `json:"username"`
-	TeamID    int       `json:"-"` // internal use only
-	TeamName  string    `json:"team_name"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"-"`
-	UpdatedAt time.Time `json:"-"`
+	ID             string        `json:"id"`
+	Username       string        `json:"username"`
+	Email          string        `json:"-"`                     // адрес для уведомлений, пусто - письма не отправляются; в API не выдаётся
+	ChatHandle     string        `json:"chat_handle,omitempty"` // упоминание в чате команды, пусто - упоминается имя
+	TeamID         int           `json:"-"`                     // internal use only; основная команда, 0 - пользователь не состоит в командах
+	TeamName       string        `json:"team_name"`             // основная команда (в которую пользователь вступил первой) или команда из контекста запроса
+	Teams          []string      `json:"teams"`                 // все команды пользователя
+	IsActive       bool          `json:"is_active"`
+	Schedule       *WorkSchedule `json:"work_schedule,omitempty"`    // nil - доступен в любое время
+	MaxOpenReviews *int          `json:"max_open_reviews,omitempty"` // лимит одновременно открытых ревью, nil - без ограничения
+	ReviewWeight   float64       `json:"review_weight,omitempty"`    // вес при выборе ревьюеров, 0 - не загружен (DefaultReviewWeight)
+	Seniority      Seniority     `json:"seniority,omitempty"`
+	DigestOptOut   bool          `json:"digest_opt_out,omitempty"` // не отправлять сводки ожидающих ревью
+	QuietHours     *QuietHours   `json:"quiet_hours,omitempty"`    // nil - уведомления в любое время
+	CreatedAt      time.Time     `json:"-"`
+	UpdatedAt      time.Time     `json:"-"`
+}
+
+// MarshalJSON скрывает адрес email: вместо него в ответ попадает признак email_configured
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	return json.Marshal(struct {
+		user
+		EmailConfigured bool `json:"email_configured"`
+	}{user: user(u), EmailConfigured: u.Email != ""})
+}
+
+// EffectiveReviewWeight возвращает вес пользователя при выборе ревьюеров
+func (u *User) EffectiveReviewWeight() float64 {
+	if u.ReviewWeight <= 0 {
+		return DefaultReviewWeight
+	}
+	return u.ReviewWeight
+}
+
+// IsSenior проверяет, что пользователь - senior
+func (u *User) IsSenior() bool {
+	return u.Seniority == SenioritySenior
+}
+
+// UserSettings - изменение настроек пользователя. nil-поля не изменяются
+type UserSettings struct {
+	ReviewWeight *float64
+	Seniority    *Seniority
+	DigestOptOut *bool
+	Email        *string // "" удаляет адрес
+	ChatHandle   *string // "" удаляет упоминание
+}
+
+// IsEmpty проверяет, что ни одна настройка не изменяется
+func (s *UserSettings) IsEmpty() bool {
+	return s.ReviewWeight == nil && s.Seniority == nil && s.DigestOptOut == nil && s.Email == nil && s.ChatHandle == nil
+}
+
+// Validate проверяет значения изменяемых настроек
+func (s *UserSettings) Validate() error {
+	if s.ReviewWeight != nil && (*s.ReviewWeight <= 0 || *s.ReviewWeight > MaxReviewWeight) {
+		return fmt.Errorf("review_weight must be in (0, %g]", MaxReviewWeight)
+	}
+	if s.Seniority != nil && !s.Seniority.IsValid() {
+		return fmt.Errorf("unknown seniority: %s", *s.Seniority)
+	}
+	if s.Email != nil && *s.Email != "" {
+		if err := ValidateEmail(*s.Email); err != nil {
+			return err
+		}
+	}
+	if s.ChatHandle != nil && *s.ChatHandle != "" && !ValidChatHandle(*s.ChatHandle) {
+		return fmt.Errorf("chat_handle must be @name (Mattermost) or <@ID> (Slack) of at most %d characters", MaxChatHandleLength)
+	}
+	return nil
+}
+
+// ValidateEmail проверяет, что email - один адрес без отображаемого имени
+func ValidateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return fmt.Errorf("invalid email: %q", email)
+	}
+	return nil
 }
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// AssignmentMode определяет, как выбираются ревьюеры среди кандидатов
+type AssignmentMode string
+
+const (
+	// AssignmentModeRandom - случайный выбор
+	AssignmentModeRandom AssignmentMode = "random"
+	// AssignmentModeWorkingHours - сначала те, у кого сейчас рабочее время, затем те, у кого оно начнётся раньше
+	AssignmentModeWorkingHours AssignmentMode = "working_hours"
+)
+
+// IsValid проверяет, что режим известен
+func (m AssignmentMode) IsValid() bool {
+	switch m {
+	case AssignmentModeRandom, AssignmentModeWorkingHours:
+		return true
+	default:
+		return false
+	}
+}
+
+// WorkSchedule - рабочее время пользователя в его часовом поясе.
+// Если End не позже Start, рабочий интервал переходит через полночь
+type WorkSchedule struct {
+	Timezone string `json:"timezone"` // IANA, например "Europe/Moscow"
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+	Days     []int  `json:"days"`     // ISO: 1 - понедельник ... 7 - воскресенье
+}
+
+// Validate проверяет часовой пояс, формат времени и дни недели
+func (s *WorkSchedule) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return fmt.Errorf("unknown timezone: %q", s.Timezone)
+	}
+
+	start, err := parseClock(s.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseClock(s.End)
+	if err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+
+	if len(s.Days) == 0 {
+		return fmt.Errorf("at least one working day is required")
+	}
+	seen := make(map[int]bool, len(s.Days))
+	for _, d := range s.Days {
+		if d < 1 || d > 7 {
+			return fmt.Errorf("invalid day %d (expected 1..7)", d)
+		}
+		if seen[d] {
+			return fmt.Errorf("duplicate day %d", d)
+		}
+		seen[d] = true
+	}
+
+	return nil
+}
+
+// UntilWorking возвращает, сколько осталось до начала рабочего времени (0 - рабочее время идёт сейчас).
+// Для некорректного расписания возвращается 0, чтобы пользователь не терял приоритет
+func (s *WorkSchedule) UntilWorking(t time.Time) time.Duration {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return 0
+	}
+	start, err := parseClock(s.Start)
+	if err != nil {
+		return 0
+	}
+	end, err := parseClock(s.End)
+	if err != nil {
+		return 0
+	}
+
+	length := end - start
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+
+	days := make(map[time.Weekday]bool, len(s.Days))
+	for _, d := range s.Days {
+		days[time.Weekday(d%7)] = true
+	}
+
+	local := t.In(loc)
+	var (
+		nearest time.Duration
+		found   bool
+	)
+
+	// Начинаем со вчерашнего дня: ночная смена могла начаться накануне
+	for offset := -1; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		if !days[day.Weekday()] {
+			continue
+		}
+
+		from := day.Add(start)
+		to := from.Add(length)
+		if !t.Before(from) && t.Before(to) {
+			return 0
+		}
+
+		if from.After(t) && (!found || from.Sub(t) < nearest) {
+			nearest = from.Sub(t)
+			found = true
+		}
+	}
+
+	return nearest
+}
+
+// IsWorkingAt проверяет, попадает ли момент t в рабочее время
+func (s *WorkSchedule) IsWorkingAt(t time.Time) bool {
+	return s.UntilWorking(t) == 0
+}
+
+// parseClock разбирает время в формате HH:MM и возвращает смещение от полуночи
+func parseClock(v string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", v)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
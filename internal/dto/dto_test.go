@@ -2,6 +2,7 @@ package dto
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -29,6 +30,16 @@ func TestReassignReviewerRequest_Validate(t *testing.T) {
 			req:     ReassignReviewerRequest{PullRequestID: "pr1", OldUserID: ""},
 			wantErr: true,
 		},
+		{
+			name:    "requested reviewer",
+			req:     ReassignReviewerRequest{PullRequestID: "pr1", OldUserID: "u1", NewUserID: "u2"},
+			wantErr: false,
+		},
+		{
+			name:    "requested reviewer is the old one",
+			req:     ReassignReviewerRequest{PullRequestID: "pr1", OldUserID: "u1", NewUserID: "u1"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +107,55 @@ func TestCreateTeamRequest_Validate(t *testing.T) {
 	}
 }
 
+func TestUpdateTeamRequest_Validate(t *testing.T) {
+	valid := UpdateTeamRequest{TeamName: "backend", RemoveMembers: []string{"u1"}}
+	assert.NoError(t, valid.Validate())
+
+	empty := UpdateTeamRequest{NewTeamName: "platform"}
+	assert.Error(t, empty.Validate())
+}
+
+func TestDeleteTeamRequest_Validate(t *testing.T) {
+	valid := DeleteTeamRequest{TeamName: "backend"}
+	assert.NoError(t, valid.Validate())
+
+	empty := DeleteTeamRequest{}
+	assert.Error(t, empty.Validate())
+}
+
+func TestSetReviewerRuleRequest_Validate(t *testing.T) {
+	valid := SetReviewerRuleRequest{TeamName: "backend", AuthorID: "u1", ReviewerID: "u2", Kind: "prefer"}
+	assert.NoError(t, valid.Validate())
+
+	noKind := SetReviewerRuleRequest{TeamName: "backend", AuthorID: "u1", ReviewerID: "u2"}
+	assert.Error(t, noKind.Validate())
+
+	noReviewer := SetReviewerRuleRequest{TeamName: "backend", AuthorID: "u1", Kind: "forbid"}
+	assert.Error(t, noReviewer.Validate())
+}
+
+func TestSetReviewCapacityRequest_Validate(t *testing.T) {
+	limit, negative := 3, -1
+
+	assert.NoError(t, (&SetReviewCapacityRequest{UserID: "u1", MaxOpenReviews: &limit}).Validate())
+	assert.NoError(t, (&SetReviewCapacityRequest{UserID: "u1"}).Validate())
+	assert.Error(t, (&SetReviewCapacityRequest{UserID: "u1", MaxOpenReviews: &negative}).Validate())
+	assert.Error(t, (&SetReviewCapacityRequest{MaxOpenReviews: &limit}).Validate())
+}
+
+func TestAddAbsenceRequest_Validate(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	valid := AddAbsenceRequest{UserID: "u1", StartsAt: start, EndsAt: start.Add(24 * time.Hour), Reason: "vacation"}
+	assert.NoError(t, valid.Validate())
+
+	noEnd := AddAbsenceRequest{UserID: "u1", StartsAt: start}
+	assert.Error(t, noEnd.Validate())
+
+	noUser := AddAbsenceRequest{StartsAt: start, EndsAt: start.Add(time.Hour)}
+	assert.Error(t, noUser.Validate())
+}
+
 func TestSetIsActiveRequest_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -196,3 +256,56 @@ func TestMergePRRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPreviewPRRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&PreviewPRRequest{AuthorID: "u1"}).Validate())
+	assert.NoError(t, (&PreviewPRRequest{AuthorID: "u1", TeamName: "backend"}).Validate())
+	assert.Error(t, (&PreviewPRRequest{TeamName: "backend"}).Validate())
+}
+
+func TestChangeReviewerRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&ChangeReviewerRequest{PullRequestID: "pr1", UserID: "u2", ActorID: "u1"}).Validate())
+	assert.Error(t, (&ChangeReviewerRequest{PullRequestID: "pr1", UserID: "u2"}).Validate())
+	assert.Error(t, (&ChangeReviewerRequest{PullRequestID: "pr1", ActorID: "u1"}).Validate())
+}
+
+func TestSetReviewSLARequest_Validate(t *testing.T) {
+	sla := &domain.ReviewSLA{Hours: 24, Action: domain.SLAActionReassign}
+
+	assert.NoError(t, (&SetReviewSLARequest{TeamName: "backend", ReviewSLA: sla}).Validate())
+	assert.NoError(t, (&SetReviewSLARequest{TeamName: "backend"}).Validate())
+	assert.Error(t, (&SetReviewSLARequest{ReviewSLA: sla}).Validate())
+	assert.Error(t, (&SetReviewSLARequest{TeamName: "backend", ReviewSLA: &domain.ReviewSLA{Hours: 24}}).Validate())
+}
+
+func TestSubmitReviewRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&SubmitReviewRequest{PullRequestID: "pr1", UserID: "u2"}).Validate())
+	assert.Error(t, (&SubmitReviewRequest{PullRequestID: "pr1"}).Validate())
+	assert.Error(t, (&SubmitReviewRequest{UserID: "u2"}).Validate())
+}
+
+func TestTriggerJobRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&TriggerJobRequest{JobName: "review_sla"}).Validate())
+	assert.Error(t, (&TriggerJobRequest{}).Validate())
+}
+
+func TestSetQuietHoursRequest_Validate(t *testing.T) {
+	quiet := &domain.QuietHours{Timezone: "UTC", Start: "22:00", End: "08:00"}
+
+	assert.NoError(t, (&SetQuietHoursRequest{UserID: "u1", QuietHours: quiet}).Validate())
+	assert.NoError(t, (&SetQuietHoursRequest{UserID: "u1"}).Validate())
+	assert.Error(t, (&SetQuietHoursRequest{QuietHours: quiet}).Validate())
+}
+
+func TestUpdateUserSettingsRequest_DigestOptOut(t *testing.T) {
+	optOut := true
+
+	assert.NoError(t, (&UpdateUserSettingsRequest{UserID: "u1", DigestOptOut: &optOut}).Validate())
+	assert.Error(t, (&UpdateUserSettingsRequest{UserID: "u1"}).Validate())
+}
+
+func TestUpdateUserSettingsRequest_Email(t *testing.T) {
+	email := ""
+
+	assert.NoError(t, (&UpdateUserSettingsRequest{UserID: "u1", Email: &email}).Validate())
+}
@@ -1,29 +1,138 @@
 package dto
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
 // CreateTeamRequest - запрос на создание команды
 type CreateTeamRequest struct {
-	TeamName string              `json:"team_name"`
-	Members  []TeamMemberRequest `json:"members"`
+	TeamName           string              `json:"team_name"`
+	ParentTeamName     string              `json:"parent_team_name,omitempty"`
+	Members            []TeamMemberRequest `json:"members"`
+	TransferPolicy     string              `json:"transfer_policy,omitempty"`      // reject (по умолчанию), move, skip или join
+	MinSeniorReviewers int                 `json:"min_senior_reviewers,omitempty"` // сколько ревьюеров PR должны быть senior
 }
 
 // TeamMemberRequest - информация о члене команды
 type TeamMemberRequest struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
+	Email    string `json:"email,omitempty"` // отсутствует - оставить сохранённый адрес
 	IsActive bool   `json:"is_active"`
 }
 
+// UpdateTeamRequest - запрос на изменение команды
+type UpdateTeamRequest struct {
+	TeamName           string              `json:"team_name"`
+	NewTeamName        string              `json:"new_team_name,omitempty"`
+	AddMembers         []TeamMemberRequest `json:"add_members,omitempty"`
+	RemoveMembers      []string            `json:"remove_members,omitempty"`
+	TransferPolicy     string              `json:"transfer_policy,omitempty"`      // reject (по умолчанию), move, skip или join
+	ParentTeamName     *string             `json:"parent_team_name,omitempty"`     // отсутствует - не менять, "" - сделать корневой
+	MinSeniorReviewers *int                `json:"min_senior_reviewers,omitempty"` // отсутствует - не менять
+	ChatWebhookURL     *string             `json:"chat_webhook_url,omitempty"`     // отсутствует - не менять, "" - удалить вебхук
+}
+
+// DeleteTeamRequest - запрос на удаление команды
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+// SetReviewerRuleRequest - запрос на создание или изменение правила для пары автор-ревьюер
+type SetReviewerRuleRequest struct {
+	TeamName   string `json:"team_name"`
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Kind       string `json:"kind"` // prefer или forbid
+}
+
+// RemoveReviewerRuleRequest - запрос на удаление правила для пары автор-ревьюер
+type RemoveReviewerRuleRequest struct {
+	TeamName   string `json:"team_name"`
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+}
+
 // SetIsActiveRequest - запрос на изменение статуса активности пользователя
 type SetIsActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
 }
 
+// SetWorkScheduleRequest - запрос на изменение рабочего времени пользователя.
+// work_schedule: null сбрасывает расписание
+type SetWorkScheduleRequest struct {
+	UserID       string               `json:"user_id"`
+	WorkSchedule *domain.WorkSchedule `json:"work_schedule"`
+}
+
+// SetQuietHoursRequest - запрос на изменение тихих часов пользователя.
+// quiet_hours: null сбрасывает их
+type SetQuietHoursRequest struct {
+	UserID     string             `json:"user_id"`
+	QuietHours *domain.QuietHours `json:"quiet_hours"`
+}
+
+// SetReviewSLARequest - запрос на изменение SLA ревью команды.
+// review_sla: null снимает SLA
+type SetReviewSLARequest struct {
+	TeamName  string            `json:"team_name"`
+	ReviewSLA *domain.ReviewSLA `json:"review_sla"`
+}
+
+// SubmitReviewRequest - отметка о том, что ревьюер отправил ревью
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// SetReviewCapacityRequest - запрос на изменение лимита открытых ревью пользователя.
+// max_open_reviews: null снимает ограничение
+type SetReviewCapacityRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+// UpdateUserSettingsRequest - запрос на изменение настроек пользователя.
+// Отсутствующие поля не изменяются
+type UpdateUserSettingsRequest struct {
+	UserID       string   `json:"user_id"`
+	ReviewWeight *float64 `json:"review_weight,omitempty"`
+	Seniority    *string  `json:"seniority,omitempty"` // junior, middle или senior
+	DigestOptOut *bool    `json:"digest_opt_out,omitempty"`
+	Email        *string  `json:"email,omitempty"`       // "" - удалить адрес
+	ChatHandle   *string  `json:"chat_handle,omitempty"` // "" - удалить упоминание
+}
+
+// AddAbsenceRequest - запрос на регистрацию отсутствия пользователя
+type AddAbsenceRequest struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// CancelAbsenceRequest - запрос на отмену отсутствия
+type CancelAbsenceRequest struct {
+	UserID    string `json:"user_id"`
+	AbsenceID int64  `json:"absence_id"`
+}
+
 // CreatePRRequest - запрос на создание PR
 type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	TeamName        string `json:"team_name,omitempty"` // обязательно, если автор состоит в нескольких командах
+}
+
+// PreviewPRRequest - запрос на предварительный подбор ревьюеров для PR
+type PreviewPRRequest struct {
+	AuthorID string `json:"author_id"`
+	TeamName string `json:"team_name,omitempty"` // обязательно, если автор состоит в нескольких командах
 }
 
 // MergePRRequest - запрос на merge PR
@@ -35,6 +144,19 @@ type MergePRRequest struct {
 type ReassignReviewerRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id,omitempty"` // пусто - замена выбирается автоматически
+}
+
+// ChangeReviewerRequest - запрос на ручное добавление или снятие ревьюера
+type ChangeReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	ActorID       string `json:"actor_id"` // кто вносит изменение, записывается в журнал
+}
+
+// TriggerJobRequest - запрос на запуск фоновой задачи вне расписания
+type TriggerJobRequest struct {
+	JobName string `json:"job_name"`
 }
 
 // Методы для валидации запросов
@@ -46,6 +168,49 @@ func (r *CreateTeamRequest) Validate() error {
 	return nil
 }
 
+func (r *UpdateTeamRequest) Validate() error {
+	if r.TeamName == "" {
+		return ErrMissingField("team_name")
+	}
+	return nil
+}
+
+func (r *DeleteTeamRequest) Validate() error {
+	if r.TeamName == "" {
+		return ErrMissingField("team_name")
+	}
+	return nil
+}
+
+func (r *SetReviewerRuleRequest) Validate() error {
+	if r.TeamName == "" {
+		return ErrMissingField("team_name")
+	}
+	if r.AuthorID == "" {
+		return ErrMissingField("author_id")
+	}
+	if r.ReviewerID == "" {
+		return ErrMissingField("reviewer_id")
+	}
+	if r.Kind == "" {
+		return ErrMissingField("kind")
+	}
+	return nil
+}
+
+func (r *RemoveReviewerRuleRequest) Validate() error {
+	if r.TeamName == "" {
+		return ErrMissingField("team_name")
+	}
+	if r.AuthorID == "" {
+		return ErrMissingField("author_id")
+	}
+	if r.ReviewerID == "" {
+		return ErrMissingField("reviewer_id")
+	}
+	return nil
+}
+
 func (r *SetIsActiveRequest) Validate() error {
 	if r.UserID == "" {
 		return ErrMissingField("user_id")
@@ -53,6 +218,85 @@ func (r *SetIsActiveRequest) Validate() error {
 	return nil
 }
 
+func (r *SetWorkScheduleRequest) Validate() error {
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	return nil
+}
+
+func (r *SetQuietHoursRequest) Validate() error {
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	return nil
+}
+
+func (r *SetReviewSLARequest) Validate() error {
+	if r.TeamName == "" {
+		return ErrMissingField("team_name")
+	}
+	if r.ReviewSLA != nil {
+		if err := r.ReviewSLA.Validate(); err != nil {
+			return fmt.Errorf("review_sla: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *SubmitReviewRequest) Validate() error {
+	if r.PullRequestID == "" {
+		return ErrMissingField("pull_request_id")
+	}
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	return nil
+}
+
+func (r *SetReviewCapacityRequest) Validate() error {
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	if r.MaxOpenReviews != nil && *r.MaxOpenReviews < 0 {
+		return fmt.Errorf("max_open_reviews must not be negative")
+	}
+	return nil
+}
+
+func (r *UpdateUserSettingsRequest) Validate() error {
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	if r.ReviewWeight == nil && r.Seniority == nil && r.DigestOptOut == nil && r.Email == nil && r.ChatHandle == nil {
+		return fmt.Errorf("at least one setting must be provided")
+	}
+	return nil
+}
+
+func (r *AddAbsenceRequest) Validate() error {
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	if r.StartsAt.IsZero() {
+		return ErrMissingField("starts_at")
+	}
+	if r.EndsAt.IsZero() {
+		return ErrMissingField("ends_at")
+	}
+	return nil
+}
+
+func (r *CancelAbsenceRequest) Validate() error {
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	if r.AbsenceID == 0 {
+		return ErrMissingField("absence_id")
+	}
+	return nil
+}
+
 func (r *CreatePRRequest) Validate() error {
 	if r.PullRequestID == "" {
 		return ErrMissingField("pull_request_id")
@@ -66,6 +310,13 @@ func (r *CreatePRRequest) Validate() error {
 	return nil
 }
 
+func (r *PreviewPRRequest) Validate() error {
+	if r.AuthorID == "" {
+		return ErrMissingField("author_id")
+	}
+	return nil
+}
+
 func (r *MergePRRequest) Validate() error {
 	if r.PullRequestID == "" {
 		return ErrMissingField("pull_request_id")
@@ -73,6 +324,19 @@ func (r *MergePRRequest) Validate() error {
 	return nil
 }
 
+func (r *ChangeReviewerRequest) Validate() error {
+	if r.PullRequestID == "" {
+		return ErrMissingField("pull_request_id")
+	}
+	if r.UserID == "" {
+		return ErrMissingField("user_id")
+	}
+	if r.ActorID == "" {
+		return ErrMissingField("actor_id")
+	}
+	return nil
+}
+
 func (r *ReassignReviewerRequest) Validate() error {
 	if r.PullRequestID == "" {
 		return ErrMissingField("pull_request_id")
@@ -80,5 +344,15 @@ func (r *ReassignReviewerRequest) Validate() error {
 	if r.OldUserID == "" {
 		return ErrMissingField("old_user_id")
 	}
+	if r.NewUserID != "" && r.NewUserID == r.OldUserID {
+		return fmt.Errorf("new_user_id must differ from old_user_id")
+	}
+	return nil
+}
+
+func (r *TriggerJobRequest) Validate() error {
+	if r.JobName == "" {
+		return ErrMissingField("job_name")
+	}
 	return nil
 }
@@ -14,7 +14,25 @@ type ErrorDetail struct {
 
 // TeamResponse - ответ с информацией о команде
 type TeamResponse struct {
-	Team *domain.Team `json:"team"`
+	Team      *domain.Team               `json:"team"`
+	Transfers *domain.TeamTransferReport `json:"transfers,omitempty"`
+}
+
+// TeamUpdateResponse - ответ на изменение команды
+type TeamUpdateResponse struct {
+	Team    *domain.Team             `json:"team"`
+	Changes *domain.TeamChangeReport `json:"changes"`
+}
+
+// TeamDeleteResponse - ответ на удаление команды
+type TeamDeleteResponse struct {
+	TeamName string                   `json:"team_name"`
+	Changes  *domain.TeamChangeReport `json:"changes"`
+}
+
+// TeamTreeResponse - иерархия всех команд
+type TeamTreeResponse struct {
+	Teams []*domain.TeamNode `json:"teams"`
 }
 
 // UserResponse - ответ с информацией о пользователе
@@ -22,15 +40,64 @@ type UserResponse struct {
 	User *domain.User `json:"user"`
 }
 
+// UserSettingsResponse - ответ на изменение настроек пользователя.
+// Только в нём возвращается адрес email, в остальных ответах есть лишь признак email_configured
+type UserSettingsResponse struct {
+	User  *domain.User `json:"user"`
+	Email string       `json:"email,omitempty"`
+}
+
+// AbsenceResponse - ответ с периодом отсутствия
+type AbsenceResponse struct {
+	Absence *domain.Absence `json:"absence"`
+}
+
+// UserAbsencesResponse - текущие и будущие отсутствия пользователя
+type UserAbsencesResponse struct {
+	UserID   string            `json:"user_id"`
+	Absences []*domain.Absence `json:"absences"`
+}
+
+// TeamAbsencesResponse - отсутствия участников команды
+type TeamAbsencesResponse struct {
+	TeamName string            `json:"team_name"`
+	Absences []*domain.Absence `json:"absences"`
+}
+
+// ReviewerRuleResponse - ответ с правилом назначения ревьюера
+type ReviewerRuleResponse struct {
+	Rule *domain.ReviewerRule `json:"rule"`
+}
+
+// ReviewerRulesResponse - правила назначения ревьюеров команды
+type ReviewerRulesResponse struct {
+	TeamName string                 `json:"team_name"`
+	Rules    []*domain.ReviewerRule `json:"rules"`
+}
+
+// EscalationsResponse - события эскалации просроченных ревью PR
+type EscalationsResponse struct {
+	PullRequestID string                     `json:"pull_request_id"`
+	Escalations   []*domain.ReviewEscalation `json:"escalations"`
+}
+
 // PRResponse - ответ с информацией о PR
 type PRResponse struct {
 	PR *domain.PullRequest `json:"pull_request"`
+	// Assignment - объяснение выбора ревьюеров, только при создании PR
+	Assignment *domain.AssignmentExplanation `json:"assignment,omitempty"`
+}
+
+// PreviewPRResponse - ответ с ревьюерами, которые были бы назначены на PR
+type PreviewPRResponse struct {
+	*domain.AssignmentPreview
 }
 
 // ReassignResponse - ответ с информацией о переназначенном ревьюере
 type ReassignResponse struct {
-	PR         *domain.PullRequest `json:"pull_request"`
-	ReplacedBy string              `json:"replaced_by"`
+	PR         *domain.PullRequest           `json:"pull_request"`
+	ReplacedBy string                        `json:"replaced_by"`
+	Assignment *domain.AssignmentExplanation `json:"assignment"`
 }
 
 // UserReviewsResponse - ответ с информацией о PR, ожидающих ревью от пользователя
@@ -56,3 +123,19 @@ func ToPRShort(pr *domain.PullRequest) *PRShort {
 		Status:          pr.Status,
 	}
 }
+
+// JobsResponse - фоновые задачи с последними запусками
+type JobsResponse struct {
+	Jobs []*domain.JobInfo `json:"jobs"`
+}
+
+// JobRunsResponse - история запусков фоновой задачи
+type JobRunsResponse struct {
+	JobName string           `json:"job_name"`
+	Runs    []*domain.JobRun `json:"runs"`
+}
+
+// JobRunResponse - запуск фоновой задачи
+type JobRunResponse struct {
+	Run *domain.JobRun `json:"run"`
+}
@@ -0,0 +1,112 @@
+package grpcserver
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	reviewerv1 "github.com/chilly266futon/reviewer-assignment-service/pkg/pb/reviewer/v1"
+)
+
+func toProtoUser(u *domain.User) *reviewerv1.User {
+	return &reviewerv1.User{
+		Id:       u.ID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+		IsActive: u.IsActive,
+		Teams:    u.Teams,
+	}
+}
+
+func toProtoTeam(t *domain.Team) *reviewerv1.Team {
+	members := make([]*reviewerv1.User, len(t.Members))
+	for i, m := range t.Members {
+		members[i] = toProtoUser(m)
+	}
+
+	return &reviewerv1.Team{
+		TeamName:       t.Name,
+		Members:        members,
+		ParentTeamName: t.ParentName,
+		Ancestors:      t.Ancestors,
+		Subteams:       toProtoTeamNodes(t.Subteams),
+	}
+}
+
+func toProtoTeamNodes(nodes []*domain.TeamNode) []*reviewerv1.TeamNode {
+	result := make([]*reviewerv1.TeamNode, len(nodes))
+	for i, n := range nodes {
+		result[i] = &reviewerv1.TeamNode{
+			TeamName:          n.Name,
+			MembersCount:      int32(n.MembersCount),
+			TotalMembersCount: int32(n.TotalMembersCount),
+			OpenPrsCount:      int32(n.OpenPRsCount),
+			TotalOpenPrsCount: int32(n.TotalOpenPRsCount),
+			Children:          toProtoTeamNodes(n.Children),
+		}
+	}
+	return result
+}
+
+func fromProtoTransferPolicy(policy reviewerv1.TransferPolicy) domain.TransferPolicy {
+	switch policy {
+	case reviewerv1.TransferPolicy_TRANSFER_POLICY_MOVE:
+		return domain.TransferPolicyMove
+	case reviewerv1.TransferPolicy_TRANSFER_POLICY_SKIP:
+		return domain.TransferPolicySkip
+	case reviewerv1.TransferPolicy_TRANSFER_POLICY_JOIN:
+		return domain.TransferPolicyJoin
+	default:
+		return domain.TransferPolicyReject
+	}
+}
+
+func toProtoTransfers(transfers []domain.MemberTransfer) []*reviewerv1.MemberTransfer {
+	result := make([]*reviewerv1.MemberTransfer, len(transfers))
+	for i, t := range transfers {
+		result[i] = &reviewerv1.MemberTransfer{
+			UserId:          t.UserID,
+			FromTeam:        t.FromTeam,
+			ReleasedReviews: t.ReleasedReviews,
+		}
+	}
+	return result
+}
+
+func toProtoStatus(status string) reviewerv1.PullRequestStatus {
+	switch status {
+	case domain.StatusOpen:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case domain.StatusMerged:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func toProtoPR(pr *domain.PullRequest) *reviewerv1.PullRequest {
+	result := &reviewerv1.PullRequest{
+		PullRequestId:     pr.ID,
+		PullRequestName:   pr.Name,
+		AuthorId:          pr.AuthorID,
+		TeamName:          pr.TeamName,
+		Status:            toProtoStatus(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		CreatedAt:         timestamppb.New(pr.CreatedAt),
+		Version:           int32(pr.Version),
+	}
+
+	if pr.MergedAt != nil {
+		result.MergedAt = timestamppb.New(*pr.MergedAt)
+	}
+
+	return result
+}
+
+func toProtoPRShort(pr *domain.PullRequest) *reviewerv1.PullRequestShort {
+	return &reviewerv1.PullRequestShort{
+		PullRequestId:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorId:        pr.AuthorID,
+		Status:          toProtoStatus(pr.Status),
+	}
+}
@@ -0,0 +1,88 @@
+package grpcserver
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	serviceErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+const errorDomain = "reviewer-assignment-service"
+
+// toStatus мапит ошибку из service layer в gRPC статус.
+// Код ошибки HTTP API передаётся в ErrorInfo.Reason
+func toStatus(err error, logger *zap.Logger) error {
+	var (
+		code   codes.Code
+		reason string
+	)
+
+	switch {
+	case errors.Is(err, serviceErrors.ErrNotFound):
+		code, reason = codes.NotFound, serviceErrors.CodeNotFound
+	case errors.Is(err, serviceErrors.ErrTeamExists):
+		code, reason = codes.AlreadyExists, serviceErrors.CodeTeamExists
+	case errors.Is(err, serviceErrors.ErrPRExists):
+		code, reason = codes.AlreadyExists, serviceErrors.CodePRExists
+	case errors.Is(err, serviceErrors.ErrPRMerged):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodePRMerged
+	case errors.Is(err, serviceErrors.ErrNotAssigned):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeNotAssigned
+	case errors.Is(err, serviceErrors.ErrNoCandidate):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeNoCandidate
+	case errors.Is(err, serviceErrors.ErrUserInOtherTeam):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeUserInOtherTeam
+	case errors.Is(err, serviceErrors.ErrAlreadyAssigned):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeAlreadyAssigned
+	case errors.Is(err, serviceErrors.ErrReviewerInactive):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeReviewerInactive
+	case errors.Is(err, serviceErrors.ErrAuthorAsReviewer):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeAuthorAsReviewer
+	case errors.Is(err, serviceErrors.ErrNotTeamMember):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeNotTeamMember
+	case errors.Is(err, serviceErrors.ErrTooManyReviewers):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeTooManyReviewers
+	case errors.Is(err, serviceErrors.ErrReviewerAtCapacity):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeReviewerAtCapacity
+	case errors.Is(err, serviceErrors.ErrReviewerForbidden):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeReviewerForbidden
+	case errors.Is(err, serviceErrors.ErrSeniorReviewerRequired):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeSeniorReviewerRequired
+	case errors.Is(err, serviceErrors.ErrReviewerAbsent):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeReviewerAbsent
+	case errors.Is(err, serviceErrors.ErrJobRunning):
+		code, reason = codes.FailedPrecondition, serviceErrors.CodeJobRunning
+	case errors.Is(err, serviceErrors.ErrVersionConflict):
+		code, reason = codes.Aborted, serviceErrors.CodeConflict
+	case errors.Is(err, serviceErrors.ErrInvalidInput):
+		code, reason = codes.InvalidArgument, "INVALID_REQUEST"
+	default:
+		logger.Error("internal server error", zap.Error(err))
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	return newStatus(code, reason, err.Error())
+}
+
+// invalidArgument возвращает ошибку валидации запроса
+func invalidArgument(message string) error {
+	return newStatus(codes.InvalidArgument, "INVALID_REQUEST", message)
+}
+
+// newStatus создаёт gRPC статус с ErrorInfo
+func newStatus(code codes.Code, reason, message string) error {
+	st := status.New(code, message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,57 @@
+package grpcserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	serviceErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{"not found", serviceErrors.ErrNotFound, codes.NotFound, serviceErrors.CodeNotFound},
+		{"team exists", serviceErrors.ErrTeamExists, codes.AlreadyExists, serviceErrors.CodeTeamExists},
+		{"pr exists", serviceErrors.ErrPRExists, codes.AlreadyExists, serviceErrors.CodePRExists},
+		{"pr merged", serviceErrors.ErrPRMerged, codes.FailedPrecondition, serviceErrors.CodePRMerged},
+		{"not assigned", serviceErrors.ErrNotAssigned, codes.FailedPrecondition, serviceErrors.CodeNotAssigned},
+		{"no candidate", serviceErrors.ErrNoCandidate, codes.FailedPrecondition, serviceErrors.CodeNoCandidate},
+		{"user in other team", serviceErrors.ErrUserInOtherTeam, codes.FailedPrecondition, serviceErrors.CodeUserInOtherTeam},
+		{"already assigned", serviceErrors.ErrAlreadyAssigned, codes.FailedPrecondition, serviceErrors.CodeAlreadyAssigned},
+		{"too many reviewers", serviceErrors.ErrTooManyReviewers, codes.FailedPrecondition, serviceErrors.CodeTooManyReviewers},
+		{"reviewer at capacity", serviceErrors.ErrReviewerAtCapacity, codes.FailedPrecondition, serviceErrors.CodeReviewerAtCapacity},
+		{"reviewer forbidden", serviceErrors.ErrReviewerForbidden, codes.FailedPrecondition, serviceErrors.CodeReviewerForbidden},
+		{"senior reviewer required", serviceErrors.ErrSeniorReviewerRequired, codes.FailedPrecondition, serviceErrors.CodeSeniorReviewerRequired},
+		{"reviewer absent", serviceErrors.ErrReviewerAbsent, codes.FailedPrecondition, serviceErrors.CodeReviewerAbsent},
+		{"version conflict", serviceErrors.ErrVersionConflict, codes.Aborted, serviceErrors.CodeConflict},
+		{"wrapped invalid input", fmt.Errorf("%w: bad", serviceErrors.ErrInvalidInput), codes.InvalidArgument, "INVALID_REQUEST"},
+		{"unknown error", assert.AnError, codes.Internal, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(toStatus(tt.err, zap.NewNop()))
+			require.True(t, ok)
+			assert.Equal(t, tt.wantCode, st.Code())
+
+			var reason string
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.ErrorInfo); ok {
+					reason = info.GetReason()
+				}
+			}
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package grpcserver
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
+	reviewerv1 "github.com/chilly266futon/reviewer-assignment-service/pkg/pb/reviewer/v1"
+)
+
+// PRServer реализует gRPC PullRequestService
+type PRServer struct {
+	reviewerv1.UnimplementedPullRequestServiceServer
+
+	prService *service.PRService
+	logger    *zap.Logger
+}
+
+// NewPRServer создаёт gRPC сервер для Pull Requests
+func NewPRServer(prService *service.PRService, logger *zap.Logger) *PRServer {
+	return &PRServer{
+		prService: prService,
+		logger:    logger,
+	}
+}
+
+// CreatePullRequest создаёт PR с автоматическим назначением ревьюеров
+func (s *PRServer) CreatePullRequest(ctx context.Context, req *reviewerv1.CreatePullRequestRequest) (*reviewerv1.CreatePullRequestResponse, error) {
+	switch {
+	case req.GetPullRequestId() == "":
+		return nil, invalidArgument("pull_request_id is required")
+	case req.GetPullRequestName() == "":
+		return nil, invalidArgument("pull_request_name is required")
+	case req.GetAuthorId() == "":
+		return nil, invalidArgument("author_id is required")
+	}
+
+	pr, _, err := s.prService.CreatePR(ctx, req.GetPullRequestId(), req.GetPullRequestName(), req.GetAuthorId(), req.GetTeamName())
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	return &reviewerv1.CreatePullRequestResponse{PullRequest: toProtoPR(pr)}, nil
+}
+
+// MergePullRequest помечает PR как смерженный
+func (s *PRServer) MergePullRequest(ctx context.Context, req *reviewerv1.MergePullRequestRequest) (*reviewerv1.MergePullRequestResponse, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, invalidArgument("pull_request_id is required")
+	}
+
+	pr, err := s.prService.MergePR(ctx, req.GetPullRequestId(), int(req.GetExpectedVersion()))
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	return &reviewerv1.MergePullRequestResponse{PullRequest: toProtoPR(pr)}, nil
+}
+
+// ReassignReviewer переназначает ревьюера
+func (s *PRServer) ReassignReviewer(ctx context.Context, req *reviewerv1.ReassignReviewerRequest) (*reviewerv1.ReassignReviewerResponse, error) {
+	switch {
+	case req.GetPullRequestId() == "":
+		return nil, invalidArgument("pull_request_id is required")
+	case req.GetOldUserId() == "":
+		return nil, invalidArgument("old_user_id is required")
+	}
+
+	newReviewerID, pr, _, err := s.prService.ReassignReviewer(ctx, req.GetPullRequestId(), req.GetOldUserId(), "", int(req.GetExpectedVersion()))
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	return &reviewerv1.ReassignReviewerResponse{
+		PullRequest: toProtoPR(pr),
+		ReplacedBy:  newReviewerID,
+	}, nil
+}
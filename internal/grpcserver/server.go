@@ -0,0 +1,69 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
+	reviewerv1 "github.com/chilly266futon/reviewer-assignment-service/pkg/pb/reviewer/v1"
+)
+
+// NewServer создаёт и настраивает gRPC сервер
+func NewServer(
+	teamService *service.TeamService,
+	userService *service.UserService,
+	prService *service.PRService,
+	logger *zap.Logger,
+) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			recoveryInterceptor(logger),
+			loggingInterceptor(logger),
+		),
+	)
+
+	reviewerv1.RegisterTeamServiceServer(srv, NewTeamServer(teamService, logger))
+	reviewerv1.RegisterUserServiceServer(srv, NewUserServer(userService, logger))
+	reviewerv1.RegisterPullRequestServiceServer(srv, NewPRServer(prService, logger))
+
+	return srv
+}
+
+// recoveryInterceptor превращает панику в handler'е в ошибку Internal
+func recoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.Error("panic in grpc handler",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", p),
+				)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// loggingInterceptor логирует вызовы, аналогично middleware HTTP сервера
+func loggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		logger.Info("grpc request",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", time.Since(start)),
+		)
+
+		return resp, err
+	}
+}
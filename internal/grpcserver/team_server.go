@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
+	reviewerv1 "github.com/chilly266futon/reviewer-assignment-service/pkg/pb/reviewer/v1"
+)
+
+// TeamServer реализует gRPC TeamService
+type TeamServer struct {
+	reviewerv1.UnimplementedTeamServiceServer
+
+	teamService *service.TeamService
+	logger      *zap.Logger
+}
+
+// NewTeamServer создаёт gRPC сервер для команд
+func NewTeamServer(teamService *service.TeamService, logger *zap.Logger) *TeamServer {
+	return &TeamServer{
+		teamService: teamService,
+		logger:      logger,
+	}
+}
+
+// AddTeam создаёт команду с участниками
+func (s *TeamServer) AddTeam(ctx context.Context, req *reviewerv1.AddTeamRequest) (*reviewerv1.AddTeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	input := &service.CreateTeamInput{
+		TeamName:       req.GetTeamName(),
+		ParentTeamName: req.GetParentTeamName(),
+		Members:        make([]service.TeamMemberInput, len(req.GetMembers())),
+		TransferPolicy: fromProtoTransferPolicy(req.GetTransferPolicy()),
+	}
+
+	for i, m := range req.GetMembers() {
+		input.Members[i] = service.TeamMemberInput{
+			UserID:   m.GetUserId(),
+			Username: m.GetUsername(),
+			IsActive: m.GetIsActive(),
+		}
+	}
+
+	team, transfers, err := s.teamService.CreateTeam(ctx, input)
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	return &reviewerv1.AddTeamResponse{
+		Team:           toProtoTeam(team),
+		MovedMembers:   toProtoTransfers(transfers.Moved),
+		SkippedMembers: toProtoTransfers(transfers.Skipped),
+		JoinedMembers:  toProtoTransfers(transfers.Joined),
+	}, nil
+}
+
+// GetTeam возвращает команду по имени
+func (s *TeamServer) GetTeam(ctx context.Context, req *reviewerv1.GetTeamRequest) (*reviewerv1.GetTeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+
+	team, err := s.teamService.GetTeam(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	return &reviewerv1.GetTeamResponse{Team: toProtoTeam(team)}, nil
+}
@@ -0,0 +1,62 @@
+package grpcserver
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
+	reviewerv1 "github.com/chilly266futon/reviewer-assignment-service/pkg/pb/reviewer/v1"
+)
+
+// UserServer реализует gRPC UserService
+type UserServer struct {
+	reviewerv1.UnimplementedUserServiceServer
+
+	userService *service.UserService
+	logger      *zap.Logger
+}
+
+// NewUserServer создаёт gRPC сервер для пользователей
+func NewUserServer(userService *service.UserService, logger *zap.Logger) *UserServer {
+	return &UserServer{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// SetIsActive изменяет статус активности пользователя
+func (s *UserServer) SetIsActive(ctx context.Context, req *reviewerv1.SetIsActiveRequest) (*reviewerv1.SetIsActiveResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	user, err := s.userService.SetIsActive(ctx, req.GetUserId(), req.GetIsActive())
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	return &reviewerv1.SetIsActiveResponse{User: toProtoUser(user)}, nil
+}
+
+// GetReview возвращает PR'ы, где пользователь назначен ревьюером
+func (s *UserServer) GetReview(ctx context.Context, req *reviewerv1.GetReviewRequest) (*reviewerv1.GetReviewResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+
+	prs, err := s.userService.GetUserReviews(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	prShorts := make([]*reviewerv1.PullRequestShort, len(prs))
+	for i, pr := range prs {
+		prShorts[i] = toProtoPRShort(pr)
+	}
+
+	return &reviewerv1.GetReviewResponse{
+		UserId:       req.GetUserId(),
+		PullRequests: prShorts,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/dto"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
+)
+
+const (
+	defaultJobRunsLimit = 20
+	maxJobRunsLimit     = 100
+)
+
+type AdminHandler struct {
+	scheduler *service.Scheduler
+	logger    *zap.Logger
+}
+
+func NewAdminHandler(scheduler *service.Scheduler, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// Jobs возвращает фоновые задачи с последними запусками
+func (h *AdminHandler) Jobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.scheduler.Jobs(r.Context())
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.JobsResponse{Jobs: jobs}, http.StatusOK)
+}
+
+// JobRuns возвращает историю запусков фоновой задачи
+func (h *AdminHandler) JobRuns(w http.ResponseWriter, r *http.Request) {
+	jobName := r.URL.Query().Get("job_name")
+	if jobName == "" {
+		respondError(w, "INVALID_REQUEST", "job_name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultJobRunsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxJobRunsLimit {
+			respondError(w, "INVALID_REQUEST", "limit must be an integer between 1 and 100", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.scheduler.Runs(r.Context(), jobName, limit)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.JobRunsResponse{
+		JobName: jobName,
+		Runs:    runs,
+	}, http.StatusOK)
+}
+
+// TriggerJob запускает фоновую задачу вне расписания
+func (h *AdminHandler) TriggerJob(w http.ResponseWriter, r *http.Request) {
+	var req dto.TriggerJobRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	run, err := h.scheduler.Trigger(r.Context(), req.JobName)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.JobRunResponse{Run: run}, http.StatusAccepted)
+}
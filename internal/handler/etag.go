@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag выставляет ETag с версией ресурса
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", fmt.Sprintf("%q", strconv.Itoa(version)))
+}
+
+// parseIfMatch возвращает версию из заголовка If-Match.
+// 0 означает, что заголовок не передан или равен "*" и версию проверять не нужно
+func parseIfMatch(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid If-Match header: %s", r.Header.Get("If-Match"))
+	}
+
+	return version, nil
+}
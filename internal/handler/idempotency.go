@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+)
+
+// Idempotency сохраняет первый ответ на POST-запрос с заголовком Idempotency-Key
+// и возвращает его при повторных запросах с тем же ключом
+func Idempotency(idempotencyService *service.IdempotencyService, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				respondError(w, "INVALID_REQUEST", "Idempotency-Key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, "INVALID_REQUEST", "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.Sum256(body)
+			requestHash := hex.EncodeToString(hash[:])
+			scope := r.Method + " " + r.URL.Path
+
+			record, err := idempotencyService.Begin(r.Context(), key, scope, requestHash)
+			if err != nil {
+				handleServiceError(w, err, logger)
+				return
+			}
+
+			// Повторный запрос - отдаём сохранённый ответ
+			if record != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(idempotencyReplayedHeader, "true")
+				if record.ETag != "" {
+					w.Header().Set("ETag", record.ETag)
+				}
+				w.WriteHeader(record.StatusCode)
+				if _, err := w.Write(record.ResponseBody); err != nil {
+					logger.Warn("failed to write replayed response", zap.Error(err))
+				}
+				return
+			}
+
+			var buf bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&buf)
+
+			// При панике освобождаем ключ, иначе он останется занятым до истечения TTL
+			defer func() {
+				if p := recover(); p != nil {
+					_ = idempotencyService.Release(context.WithoutCancel(r.Context()), key, scope)
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+
+			// Ответ сохраняем, даже если клиент уже отключился
+			ctx := context.WithoutCancel(r.Context())
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// Внутренние ошибки не сохраняем, чтобы запрос можно было повторить
+			if status >= http.StatusInternalServerError {
+				if err := idempotencyService.Release(ctx, key, scope); err != nil {
+					logger.Error("failed to release idempotency key",
+						zap.String("key", key),
+						zap.Error(err),
+					)
+				}
+				return
+			}
+
+			if err := idempotencyService.Complete(ctx, key, scope, status, ww.Header().Get("ETag"), buf.Bytes()); err != nil {
+				logger.Error("failed to save idempotent response",
+					zap.String("key", key),
+					zap.Error(err),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
+)
+
+// fakeIdempotencyRepo - хранилище ключей в памяти
+type fakeIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newFakeIdempotencyRepo() *fakeIdempotencyRepo {
+	return &fakeIdempotencyRepo{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (r *fakeIdempotencyRepo) Reserve(_ context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := record.Scope + "|" + record.Key
+	// Незавершённый запрос, державший ключ дольше блокировки, ключ уже не держит
+	if existing, ok := r.records[id]; ok && (existing.IsCompleted() || time.Now().Before(existing.LockedUntil)) {
+		copied := *existing
+		return &copied, nil
+	}
+	copied := *record
+	r.records[id] = &copied
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepo) Complete(_ context.Context, key, scope string, statusCode int, etag string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record := r.records[scope+"|"+key]
+	record.StatusCode = statusCode
+	record.ETag = etag
+	record.ResponseBody = append([]byte(nil), body...)
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) Delete(_ context.Context, key, scope string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, scope+"|"+key)
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) DeleteExpired(context.Context) (int, error) {
+	return 0, nil
+}
+
+func (r *fakeIdempotencyRepo) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.records)
+}
+
+func newIdempotencyTestHandler(repo *fakeIdempotencyRepo, next http.Handler) http.Handler {
+	idempotencyService := service.NewIdempotencyService(repo, time.Hour, time.Minute, zap.NewNop())
+	return Idempotency(idempotencyService, zap.NewNop())(next)
+}
+
+func idempotentRequest(method, key, body string) *http.Request {
+	req := httptest.NewRequest(method, "/team/add", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	return req
+}
+
+func TestIdempotency_Replay(t *testing.T) {
+	calls := 0
+	h := newIdempotencyTestHandler(newFakeIdempotencyRepo(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		respondJSON(w, map[string]int{"call": calls}, http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	h.ServeHTTP(first, idempotentRequest(http.MethodPost, "k1", `{"team_name":"backend"}`))
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(idempotencyReplayedHeader))
+
+	second := httptest.NewRecorder()
+	h.ServeHTTP(second, idempotentRequest(http.MethodPost, "k1", `{"team_name":"backend"}`))
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "true", second.Header().Get(idempotencyReplayedHeader))
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_ReplaysETag(t *testing.T) {
+	version := 0
+	h := newIdempotencyTestHandler(newFakeIdempotencyRepo(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version++
+		setETag(w, version)
+		respondJSON(w, map[string]int{"version": version}, http.StatusOK)
+	}))
+
+	first := httptest.NewRecorder()
+	h.ServeHTTP(first, idempotentRequest(http.MethodPost, "k1", `{}`))
+	require.Equal(t, `"1"`, first.Header().Get("ETag"))
+
+	// Повтор отдаёт версию из сохранённого ответа, а не текущую
+	second := httptest.NewRecorder()
+	h.ServeHTTP(second, idempotentRequest(http.MethodPost, "k1", `{}`))
+	assert.Equal(t, "true", second.Header().Get(idempotencyReplayedHeader))
+	assert.Equal(t, `"1"`, second.Header().Get("ETag"))
+	assert.Equal(t, 1, version)
+}
+
+func TestIdempotency_KeyReusedWithDifferentBody(t *testing.T) {
+	calls := 0
+	h := newIdempotencyTestHandler(newFakeIdempotencyRepo(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "k1", `{"team_name":"backend"}`))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, idempotentRequest(http.MethodPost, "k1", `{"team_name":"frontend"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "IDEMPOTENCY_KEY_REUSED")
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	h := newIdempotencyTestHandler(newFakeIdempotencyRepo(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "k1", `{}`))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, idempotentRequest(http.MethodPost, "k1", `{}`))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "REQUEST_IN_PROGRESS")
+
+	close(finish)
+	<-done
+}
+
+func TestIdempotency_TakesOverStaleReservation(t *testing.T) {
+	repo := newFakeIdempotencyRepo()
+	calls := 0
+	h := newIdempotencyTestHandler(repo, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Запрос с этим ключом начался, но так и не завершился
+	now := time.Now()
+	repo.records["POST /team/add|k1"] = &domain.IdempotencyRecord{
+		Key:         "k1",
+		Scope:       "POST /team/add",
+		RequestHash: fmt.Sprintf("%x", sha256.Sum256([]byte(`{}`))),
+		CreatedAt:   now.Add(-2 * time.Minute),
+		ExpiresAt:   now.Add(time.Hour),
+		LockedUntil: now.Add(-time.Minute),
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, idempotentRequest(http.MethodPost, "k1", `{}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_ReleasesKeyOnServerError(t *testing.T) {
+	repo := newFakeIdempotencyRepo()
+	status := http.StatusInternalServerError
+	calls := 0
+	h := newIdempotencyTestHandler(repo, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, idempotentRequest(http.MethodPost, "k1", `{}`))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Zero(t, repo.len())
+
+	// После ошибки запрос с тем же ключом выполняется заново
+	status = http.StatusOK
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, idempotentRequest(http.MethodPost, "k1", `{}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(idempotencyReplayedHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ReleasesKeyOnPanic(t *testing.T) {
+	repo := newFakeIdempotencyRepo()
+	h := newIdempotencyTestHandler(repo, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "k1", `{}`))
+	})
+	assert.Zero(t, repo.len())
+}
+
+func TestIdempotency_Passthrough(t *testing.T) {
+	repo := newFakeIdempotencyRepo()
+	calls := 0
+	h := newIdempotencyTestHandler(repo, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// GET с ключом и POST без ключа обрабатываются без сохранения ответа
+	for i := 0; i < 2; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodGet, "k1", ""))
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "", `{}`))
+	}
+
+	assert.Equal(t, 4, calls)
+	assert.Zero(t, repo.len())
+}
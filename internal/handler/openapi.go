@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"go.uber.org/zap"
+)
+
+// docsPage - страница документации, которая рендерит /openapi.json через Swagger UI.
+// Все скрипты и стили отдаются самим сервисом из /docs/
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Reviewer Assignment Service API</title>
+  <link rel="stylesheet" href="/docs/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="/docs/swagger-ui-bundle.js"></script>
+  <script src="/docs/swagger-initializer.js"></script>
+</body>
+</html>
+`
+
+// docsCSP запрещает странице документации загружать что-либо не с самого сервиса.
+// Swagger UI задаёт стили элементам inline и показывает картинки через data: URI
+const docsCSP = "default-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:"
+
+// OpenAPIHandler отдаёт спецификацию и проверяет запросы и ответы на соответствие ей
+type OpenAPIHandler struct {
+	doc    *openapi3.T
+	router routers.Router
+	assets fs.FS
+	logger *zap.Logger
+}
+
+// NewOpenAPIHandler создаёт handler для OpenAPI-спецификации.
+// assets - файлы Swagger UI для страницы документации
+func NewOpenAPIHandler(doc *openapi3.T, assets fs.FS, logger *zap.Logger) (*OpenAPIHandler, error) {
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenAPIHandler{
+		doc:    doc,
+		router: router,
+		assets: assets,
+		logger: logger,
+	}, nil
+}
+
+// Spec возвращает спецификацию в формате JSON
+func (h *OpenAPIHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, h.doc, http.StatusOK)
+}
+
+// Docs возвращает HTML-страницу с документацией
+func (h *OpenAPIHandler) Docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", docsCSP)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(w, docsPage); err != nil {
+		h.logger.Warn("failed to write docs page", zap.Error(err))
+	}
+}
+
+// DocsAssets отдаёт файлы Swagger UI
+func (h *OpenAPIHandler) DocsAssets() http.Handler {
+	return http.StripPrefix("/docs/", http.FileServerFS(h.assets))
+}
+
+// Validation проверяет запросы и (опционально) ответы на соответствие спецификации.
+// Маршруты, отсутствующие в спецификации, пропускаются без проверки
+func (h *OpenAPIHandler) Validation(validateRequests, validateResponses bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !validateRequests && !validateResponses {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := h.router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Тело запроса читается валидатором, поэтому сохраняем его для handler'а
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, "INVALID_REQUEST", "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError: false,
+				},
+			}
+
+			if validateRequests {
+				if err := openapi3filter.ValidateRequest(r.Context(), requestInput); err != nil {
+					respondError(w, "INVALID_REQUEST", validationMessage(err), http.StatusBadRequest)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			if !validateResponses {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Буферизуем ответ, чтобы проверить его до отправки клиенту
+			rec := newResponseRecorder()
+			next.ServeHTTP(rec, r)
+
+			err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 rec.status,
+				Header:                 rec.header,
+				Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+				Options: &openapi3filter.Options{
+					IncludeResponseStatus: true,
+				},
+			})
+			if err != nil {
+				h.logger.Error("response does not match openapi spec",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Int("status", rec.status),
+					zap.Error(err),
+				)
+				respondError(w, "INTERNAL_ERROR", "internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			rec.flush(w)
+		})
+	}
+}
+
+// validationMessage возвращает краткое описание ошибки валидации без дампа схемы
+func validationMessage(err error) string {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(requestErr.Err, &schemaErr) {
+			return requestErr.Reason + ": " + schemaErr.Reason
+		}
+		if requestErr.Reason != "" {
+			return requestErr.Reason
+		}
+	}
+	return err.Error()
+}
+
+// responseRecorder буферизует ответ handler'а
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header: make(http.Header),
+		status: http.StatusOK,
+	}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// flush отправляет буферизованный ответ клиенту
+func (r *responseRecorder) flush(w http.ResponseWriter) {
+	for key, values := range r.header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(r.status)
+	_, _ = w.Write(r.body.Bytes())
+}
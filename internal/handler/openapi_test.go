@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/api"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/config"
+)
+
+func newTestOpenAPIHandler(t *testing.T) *OpenAPIHandler {
+	t.Helper()
+
+	doc, err := api.LoadSpec()
+	require.NoError(t, err)
+
+	h, err := NewOpenAPIHandler(doc, api.SwaggerUI(), zap.NewNop())
+	require.NoError(t, err)
+
+	return h
+}
+
+// Каждый маршрут API должен быть описан в спецификации
+func TestRouter_AllRoutesDocumented(t *testing.T) {
+	openAPIHandler := newTestOpenAPIHandler(t)
+	router := NewRouter(&config.Config{}, nil, nil, nil, nil, nil, openAPIHandler, nil, zap.NewNop())
+
+	undocumented := map[string]bool{
+		"/openapi.json": true,
+		"/docs":         true,
+		"/docs/*":       true,
+	}
+
+	err := chi.Walk(router.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if undocumented[route] {
+			return nil
+		}
+
+		pathItem := openAPIHandler.doc.Paths.Find(route)
+		if assert.NotNil(t, pathItem, "route %s is not documented", route) {
+			assert.NotNil(t, pathItem.GetOperation(method), "operation %s %s is not documented", method, route)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestOpenAPIValidation_Request(t *testing.T) {
+	openAPIHandler := newTestOpenAPIHandler(t)
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+	h := openAPIHandler.Validation(true, false)(next)
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+}
+
+func TestOpenAPIValidation_Response(t *testing.T) {
+	openAPIHandler := newTestOpenAPIHandler(t)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, map[string]string{"unexpected": "field"}, http.StatusOK)
+	})
+	h := openAPIHandler.Validation(true, true)(next)
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{"pull_request_id":"pr1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestDocs_ServesEmbeddedAssets(t *testing.T) {
+	h := newTestOpenAPIHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.Docs(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "default-src 'self'")
+	assert.NotContains(t, rec.Body.String(), "https://")
+
+	// Все файлы, на которые ссылается страница, встроены в бинарник
+	for _, asset := range []string{"swagger-ui.css", "swagger-ui-bundle.js", "swagger-initializer.js"} {
+		assert.Contains(t, rec.Body.String(), "/docs/"+asset)
+
+		assetRec := httptest.NewRecorder()
+		h.DocsAssets().ServeHTTP(assetRec, httptest.NewRequest(http.MethodGet, "/docs/"+asset, nil))
+		assert.Equal(t, http.StatusOK, assetRec.Code, asset)
+		assert.NotEmpty(t, assetRec.Body.Len(), asset)
+	}
+}
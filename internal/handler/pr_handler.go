@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
 	"go.uber.org/zap"
 
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/dto"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
 )
@@ -39,13 +41,38 @@ func (h *PRHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	pr, explanation, err := h.prService.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.TeamName)
 	if err != nil {
 		handleServiceError(w, err, h.logger)
 		return
 	}
 
-	respondJSON(w, dto.PRResponse{PR: pr}, http.StatusCreated)
+	setETag(w, pr.Version)
+	respondJSON(w, dto.PRResponse{PR: pr, Assignment: explanation}, http.StatusCreated)
+}
+
+// Preview показывает ревьюеров, которые были бы назначены на PR, ничего не сохраняя
+func (h *PRHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req dto.PreviewPRRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.prService.PreviewPR(r.Context(), req.AuthorID, req.TeamName)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.PreviewPRResponse{AssignmentPreview: preview}, http.StatusOK)
 }
 
 // Merge помечает PR как смерженный
@@ -63,15 +90,112 @@ func (h *PRHandler) Merge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.MergePR(r.Context(), req.PullRequestID)
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.prService.MergePR(r.Context(), req.PullRequestID, expectedVersion)
 	if err != nil {
 		handleServiceError(w, err, h.logger)
 		return
 	}
 
+	setETag(w, pr.Version)
 	respondJSON(w, dto.PRResponse{PR: pr}, http.StatusOK)
 }
 
+// AddReviewer вручную назначает ревьюера на PR
+func (h *PRHandler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	h.changeReviewer(w, r, h.prService.AddReviewer)
+}
+
+// RemoveReviewer снимает ревьюера с PR без замены
+func (h *PRHandler) RemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	h.changeReviewer(w, r, h.prService.RemoveReviewer)
+}
+
+// changeReviewer разбирает запрос на ручное изменение ревьюеров и выполняет его через change
+func (h *PRHandler) changeReviewer(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ctx context.Context, prID, userID, actorID string, expectedVersion int) (*domain.PullRequest, error),
+) {
+	var req dto.ChangeReviewerRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pr, err := change(r.Context(), req.PullRequestID, req.UserID, req.ActorID, expectedVersion)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	setETag(w, pr.Version)
+	respondJSON(w, dto.PRResponse{PR: pr}, http.StatusOK)
+}
+
+// SubmitReview отмечает, что ревьюер отправил ревью
+func (h *PRHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req dto.SubmitReviewRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(r.Context(), req.PullRequestID, req.UserID)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	setETag(w, pr.Version)
+	respondJSON(w, dto.PRResponse{PR: pr}, http.StatusOK)
+}
+
+// Escalations возвращает события эскалации просроченных ревью PR
+func (h *PRHandler) Escalations(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		respondError(w, "INVALID_REQUEST", "pull_request_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	escalations, err := h.prService.GetEscalations(r.Context(), prID)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.EscalationsResponse{
+		PullRequestID: prID,
+		Escalations:   escalations,
+	}, http.StatusOK)
+}
+
 // Reassign переназначает ревьюера
 func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 	var req dto.ReassignReviewerRequest
@@ -87,14 +211,22 @@ func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newReviewerID, pr, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	newReviewerID, pr, explanation, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, req.NewUserID, expectedVersion)
 	if err != nil {
 		handleServiceError(w, err, h.logger)
 		return
 	}
 
+	setETag(w, pr.Version)
 	respondJSON(w, dto.ReassignResponse{
 		PR:         pr,
 		ReplacedBy: newReviewerID,
+		Assignment: explanation,
 	}, http.StatusOK)
 }
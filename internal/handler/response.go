@@ -46,6 +46,34 @@ func handleServiceError(w http.ResponseWriter, err error, logger *zap.Logger) {
 		respondError(w, serviceErrors.CodeNotAssigned, err.Error(), http.StatusConflict)
 	case errors.Is(err, serviceErrors.ErrNoCandidate):
 		respondError(w, serviceErrors.CodeNoCandidate, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrUserInOtherTeam):
+		respondError(w, serviceErrors.CodeUserInOtherTeam, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrAlreadyAssigned):
+		respondError(w, serviceErrors.CodeAlreadyAssigned, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrReviewerInactive):
+		respondError(w, serviceErrors.CodeReviewerInactive, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrAuthorAsReviewer):
+		respondError(w, serviceErrors.CodeAuthorAsReviewer, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrNotTeamMember):
+		respondError(w, serviceErrors.CodeNotTeamMember, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrTooManyReviewers):
+		respondError(w, serviceErrors.CodeTooManyReviewers, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrReviewerAtCapacity):
+		respondError(w, serviceErrors.CodeReviewerAtCapacity, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrReviewerForbidden):
+		respondError(w, serviceErrors.CodeReviewerForbidden, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrSeniorReviewerRequired):
+		respondError(w, serviceErrors.CodeSeniorReviewerRequired, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrReviewerAbsent):
+		respondError(w, serviceErrors.CodeReviewerAbsent, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrJobRunning):
+		respondError(w, serviceErrors.CodeJobRunning, err.Error(), http.StatusConflict)
+	case errors.Is(err, serviceErrors.ErrVersionConflict):
+		respondError(w, serviceErrors.CodeConflict, err.Error(), http.StatusPreconditionFailed)
+	case errors.Is(err, serviceErrors.ErrIdempotencyKeyReused):
+		respondError(w, serviceErrors.CodeIdempotencyKeyReused, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, serviceErrors.ErrRequestInProgress):
+		respondError(w, serviceErrors.CodeRequestInProgress, err.Error(), http.StatusConflict)
 	case errors.Is(err, serviceErrors.ErrInvalidInput):
 		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
 	default:
@@ -10,14 +10,19 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
+	"github.com/chilly266futon/reviewer-assignment-service/internal/config"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
 )
 
 // NewRouter создаёт и настраивает HTTP router
 func NewRouter(
+	cfg *config.Config,
 	teamService *service.TeamService,
 	userService *service.UserService,
 	prService *service.PRService,
+	idempotencyService *service.IdempotencyService,
+	scheduler *service.Scheduler,
+	openAPIHandler *OpenAPIHandler,
 	pool *pgxpool.Pool,
 	logger *zap.Logger,
 ) http.Handler {
@@ -46,6 +51,12 @@ func NewRouter(
 		})
 	})
 
+	// Проверка запросов и ответов на соответствие OpenAPI-спецификации
+	r.Use(openAPIHandler.Validation(cfg.OpenAPIValidateRequests, cfg.OpenAPIValidateResponses))
+
+	// Повтор POST-запросов с тем же Idempotency-Key возвращает сохранённый ответ
+	r.Use(Idempotency(idempotencyService, logger))
+
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
@@ -64,26 +75,58 @@ func NewRouter(
 		w.Write([]byte(`{"status":"ok","database":"connected"}`))
 	})
 
+	// Документация API
+	r.Get("/openapi.json", openAPIHandler.Spec)
+	r.Get("/docs", openAPIHandler.Docs)
+	r.Handle("/docs/*", openAPIHandler.DocsAssets())
+
 	// Инициализируем handlers
 	teamHandler := NewTeamHandler(teamService, logger)
 	userHandler := NewUserHandler(userService, logger)
 	prHandler := NewPRHandler(prService, logger)
+	adminHandler := NewAdminHandler(scheduler, logger)
 
 	// API routes
 	r.Route("/team", func(r chi.Router) {
 		r.Post("/add", teamHandler.Add)
 		r.Get("/get", teamHandler.Get)
+		r.Get("/tree", teamHandler.Tree)
+		r.Get("/absences", teamHandler.Absences)
+		r.Post("/update", teamHandler.Update)
+		r.Post("/delete", teamHandler.Delete)
+		r.Get("/reviewerRules", teamHandler.ReviewerRules)
+		r.Post("/setReviewerRule", teamHandler.SetReviewerRule)
+		r.Post("/removeReviewerRule", teamHandler.RemoveReviewerRule)
+		r.Post("/setReviewSLA", teamHandler.SetReviewSLA)
 	})
 
 	r.Route("/users", func(r chi.Router) {
 		r.Post("/setIsActive", userHandler.SetIsActive)
+		r.Post("/setWorkSchedule", userHandler.SetWorkSchedule)
+		r.Post("/setQuietHours", userHandler.SetQuietHours)
+		r.Post("/setReviewCapacity", userHandler.SetReviewCapacity)
+		r.Post("/updateSettings", userHandler.UpdateSettings)
 		r.Get("/getReview", userHandler.GetReview)
+		r.Post("/addAbsence", userHandler.AddAbsence)
+		r.Post("/cancelAbsence", userHandler.CancelAbsence)
+		r.Get("/getAbsences", userHandler.GetAbsences)
 	})
 
 	r.Route("/pullRequest", func(r chi.Router) {
 		r.Post("/create", prHandler.Create)
+		r.Post("/preview", prHandler.Preview)
 		r.Post("/merge", prHandler.Merge)
 		r.Post("/reassign", prHandler.Reassign)
+		r.Post("/addReviewer", prHandler.AddReviewer)
+		r.Post("/removeReviewer", prHandler.RemoveReviewer)
+		r.Post("/submitReview", prHandler.SubmitReview)
+		r.Get("/escalations", prHandler.Escalations)
+	})
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Get("/jobs", adminHandler.Jobs)
+		r.Get("/jobRuns", adminHandler.JobRuns)
+		r.Post("/triggerJob", adminHandler.TriggerJob)
 	})
 
 	return r
@@ -3,13 +3,20 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"go.uber.org/zap"
 
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/dto"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
 )
 
+const (
+	defaultAbsenceDays = 30
+	maxAbsenceDays     = 365
+)
+
 // TeamHandler обрабатывает запросы для команд
 type TeamHandler struct {
 	teamService *service.TeamService
@@ -43,27 +50,99 @@ func (h *TeamHandler) Add(w http.ResponseWriter, r *http.Request) {
 
 	// Маппинг DTO → Service Input
 	input := &service.CreateTeamInput{
-		TeamName: req.TeamName,
-		Members:  make([]service.TeamMemberInput, len(req.Members)),
+		TeamName:           req.TeamName,
+		ParentTeamName:     req.ParentTeamName,
+		Members:            make([]service.TeamMemberInput, len(req.Members)),
+		TransferPolicy:     domain.TransferPolicy(req.TransferPolicy),
+		MinSeniorReviewers: req.MinSeniorReviewers,
 	}
 
 	for i, m := range req.Members {
 		input.Members[i] = service.TeamMemberInput{
 			UserID:   m.UserID,
 			Username: m.Username,
+			Email:    m.Email,
 			IsActive: m.IsActive,
 		}
 	}
 
 	// Вызов service
-	team, err := h.teamService.CreateTeam(r.Context(), input)
+	team, transfers, err := h.teamService.CreateTeam(r.Context(), input)
 	if err != nil {
 		handleServiceError(w, err, h.logger)
 		return
 	}
 
 	// Успешный ответ
-	respondJSON(w, dto.TeamResponse{Team: team}, http.StatusCreated)
+	respondJSON(w, dto.TeamResponse{Team: team, Transfers: transfers}, http.StatusCreated)
+}
+
+// Update переименовывает команду, добавляет и удаляет участников
+func (h *TeamHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateTeamRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	input := &service.UpdateTeamInput{
+		TeamName:           req.TeamName,
+		NewTeamName:        req.NewTeamName,
+		AddMembers:         make([]service.TeamMemberInput, len(req.AddMembers)),
+		RemoveMembers:      req.RemoveMembers,
+		TransferPolicy:     domain.TransferPolicy(req.TransferPolicy),
+		ParentTeamName:     req.ParentTeamName,
+		MinSeniorReviewers: req.MinSeniorReviewers,
+		ChatWebhookURL:     req.ChatWebhookURL,
+	}
+
+	for i, m := range req.AddMembers {
+		input.AddMembers[i] = service.TeamMemberInput{
+			UserID:   m.UserID,
+			Username: m.Username,
+			Email:    m.Email,
+			IsActive: m.IsActive,
+		}
+	}
+
+	team, changes, err := h.teamService.UpdateTeam(r.Context(), input)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.TeamUpdateResponse{Team: team, Changes: changes}, http.StatusOK)
+}
+
+// Delete удаляет команду
+func (h *TeamHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteTeamRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.teamService.DeleteTeam(r.Context(), req.TeamName)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.TeamDeleteResponse{TeamName: req.TeamName, Changes: changes}, http.StatusOK)
 }
 
 // Get возвращает команду по имени
@@ -82,3 +161,141 @@ func (h *TeamHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, team, http.StatusOK)
 }
+
+// Tree возвращает иерархию всех команд
+func (h *TeamHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.teamService.GetTeamTree(r.Context())
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.TeamTreeResponse{Teams: teams}, http.StatusOK)
+}
+
+// Absences возвращает текущие и предстоящие отсутствия участников команды
+func (h *TeamHandler) Absences(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		respondError(w, "INVALID_REQUEST", "team_name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	days := defaultAbsenceDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxAbsenceDays {
+			respondError(w, "INVALID_REQUEST", "days must be an integer between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	absences, err := h.teamService.GetTeamAbsences(r.Context(), teamName, days)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.TeamAbsencesResponse{
+		TeamName: teamName,
+		Absences: absences,
+	}, http.StatusOK)
+}
+
+// SetReviewerRule создаёт или изменяет правило назначения ревьюера автору PR
+func (h *TeamHandler) SetReviewerRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetReviewerRuleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.teamService.SetReviewerRule(r.Context(), &service.ReviewerRuleInput{
+		TeamName:   req.TeamName,
+		AuthorID:   req.AuthorID,
+		ReviewerID: req.ReviewerID,
+		Kind:       domain.ReviewerRuleKind(req.Kind),
+	})
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.ReviewerRuleResponse{Rule: rule}, http.StatusOK)
+}
+
+// RemoveReviewerRule удаляет правило для пары автор-ревьюер
+func (h *TeamHandler) RemoveReviewerRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.RemoveReviewerRuleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.teamService.RemoveReviewerRule(r.Context(), req.TeamName, req.AuthorID, req.ReviewerID)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.ReviewerRuleResponse{Rule: rule}, http.StatusOK)
+}
+
+// ReviewerRules возвращает правила назначения ревьюеров команды
+func (h *TeamHandler) ReviewerRules(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		respondError(w, "INVALID_REQUEST", "team_name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	rules, err := h.teamService.GetReviewerRules(r.Context(), teamName)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.ReviewerRulesResponse{
+		TeamName: teamName,
+		Rules:    rules,
+	}, http.StatusOK)
+}
+
+// SetReviewSLA задаёт или снимает SLA ревью команды
+func (h *TeamHandler) SetReviewSLA(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetReviewSLARequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.teamService.SetReviewSLA(r.Context(), req.TeamName, req.ReviewSLA)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.TeamResponse{Team: team}, http.StatusOK)
+}
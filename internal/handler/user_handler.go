@@ -6,6 +6,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/dto"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/service"
 )
@@ -46,6 +47,113 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, dto.UserResponse{User: user}, http.StatusOK)
 }
 
+// SetWorkSchedule изменяет рабочее время пользователя
+func (h *UserHandler) SetWorkSchedule(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetWorkScheduleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.SetWorkSchedule(r.Context(), req.UserID, req.WorkSchedule)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.UserResponse{User: user}, http.StatusOK)
+}
+
+// SetQuietHours изменяет тихие часы пользователя
+func (h *UserHandler) SetQuietHours(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetQuietHoursRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.SetQuietHours(r.Context(), req.UserID, req.QuietHours)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.UserResponse{User: user}, http.StatusOK)
+}
+
+// SetReviewCapacity изменяет лимит открытых ревью пользователя
+func (h *UserHandler) SetReviewCapacity(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetReviewCapacityRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.SetReviewCapacity(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.UserResponse{User: user}, http.StatusOK)
+}
+
+// UpdateSettings изменяет настройки пользователя
+func (h *UserHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateUserSettingsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	settings := &domain.UserSettings{
+		ReviewWeight: req.ReviewWeight,
+		DigestOptOut: req.DigestOptOut,
+		Email:        req.Email,
+		ChatHandle:   req.ChatHandle,
+	}
+	if req.Seniority != nil {
+		seniority := domain.Seniority(*req.Seniority)
+		settings.Seniority = &seniority
+	}
+
+	user, err := h.userService.UpdateSettings(r.Context(), req.UserID, settings)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.UserSettingsResponse{User: user, Email: user.Email}, http.StatusOK)
+}
+
 // GetReview возвращает PR'ы где пользователь назначен ревьюером
 func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
@@ -71,3 +179,76 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 		PullRequests: prShorts,
 	}, http.StatusOK)
 }
+
+// AddAbsence регистрирует период отсутствия пользователя
+func (h *UserHandler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddAbsenceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	absence, err := h.userService.AddAbsence(r.Context(), &service.AddAbsenceInput{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.AbsenceResponse{Absence: absence}, http.StatusCreated)
+}
+
+// CancelAbsence отменяет период отсутствия пользователя
+func (h *UserHandler) CancelAbsence(w http.ResponseWriter, r *http.Request) {
+	var req dto.CancelAbsenceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("failed to decode request", zap.Error(err))
+		respondError(w, "INVALID_REQUEST", "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	absence, err := h.userService.CancelAbsence(r.Context(), req.UserID, req.AbsenceID)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.AbsenceResponse{Absence: absence}, http.StatusOK)
+}
+
+// GetAbsences возвращает текущие и будущие отсутствия пользователя
+func (h *UserHandler) GetAbsences(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		respondError(w, "INVALID_REQUEST", "user_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	absences, err := h.userService.GetAbsences(r.Context(), userID)
+	if err != nil {
+		handleServiceError(w, err, h.logger)
+		return
+	}
+
+	respondJSON(w, dto.UserAbsencesResponse{
+		UserID:   userID,
+		Absences: absences,
+	}, http.StatusOK)
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// PRURLPlaceholder в шаблоне ссылки на PR заменяется на ID PR
+const PRURLPlaceholder = "{id}"
+
+// chatEscaper экранирует управляющие символы разметки Slack; Mattermost понимает те же сущности
+var chatEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// ChatNotifier отправляет сообщения о событиях PR во входящий вебхук Slack или Mattermost команды PR.
+// PR без команды и команды без вебхука пропускаются
+type ChatNotifier struct {
+	client        *http.Client
+	prURLTemplate string // пусто - сообщения без ссылок на PR
+	logger        *zap.Logger
+}
+
+func NewChatNotifier(prURLTemplate string, timeout time.Duration, logger *zap.Logger) *ChatNotifier {
+	return &ChatNotifier{
+		client:        &http.Client{Timeout: timeout},
+		prURLTemplate: prURLTemplate,
+		logger:        logger,
+	}
+}
+
+// chatMessage - тело запроса входящего вебхука в формате, общем для Slack и Mattermost
+type chatMessage struct {
+	Text string `json:"text"`
+}
+
+// Wants сообщает, задан ли у команды PR вебхук чата
+func (n *ChatNotifier) Wants(_ domain.PREventKind, team *domain.Team) bool {
+	return team != nil && team.ChatWebhookURL != ""
+}
+
+// NotifyPREvent отправляет сообщение о событии в чат команды PR
+func (n *ChatNotifier) NotifyPREvent(ctx context.Context, event *domain.PREvent) error {
+	if !n.Wants(event.Kind, event.Team) {
+		return nil
+	}
+
+	if err := postJSON(ctx, n.client, event.Team.ChatWebhookURL, chatMessage{Text: n.format(event)}, n.logger); err != nil {
+		return fmt.Errorf("team %s chat webhook: %w", event.Team.Name, err)
+	}
+
+	return nil
+}
+
+// format формирует текст сообщения. Назначенные ревьюеры упоминаются через их chat_handle
+func (n *ChatNotifier) format(event *domain.PREvent) string {
+	pr := n.prLink(event.PR)
+	author := chatName(event.Author)
+
+	switch event.Kind {
+	case domain.PREventReassigned:
+		replaced := "previous reviewer"
+		if event.Replaced != nil {
+			replaced = chatName(event.Replaced)
+		}
+		return fmt.Sprintf(":arrows_counterclockwise: Reviewer changed: %s by %s\n%s replaces %s",
+			pr, author, chatMentions(event.Reviewers), replaced)
+	case domain.PREventMerged:
+		text := fmt.Sprintf(":white_check_mark: Merged: %s by %s", pr, author)
+		if len(event.Reviewers) > 0 {
+			names := make([]string, len(event.Reviewers))
+			for i, reviewer := range event.Reviewers {
+				names[i] = chatName(reviewer)
+			}
+			text += "\nReviewed by: " + strings.Join(names, ", ")
+		}
+		return text
+	default:
+		return fmt.Sprintf(":eyes: Review requested: %s by %s\nReviewers: %s",
+			pr, author, chatMentions(event.Reviewers))
+	}
+}
+
+// prLink возвращает название PR со ссылкой, если задан шаблон ссылки
+func (n *ChatNotifier) prLink(pr *domain.PullRequest) string {
+	title := chatEscaper.Replace(fmt.Sprintf("%s (%s)", pr.Name, pr.ID))
+	if n.prURLTemplate == "" {
+		return "*" + title + "*"
+	}
+
+	link := strings.ReplaceAll(n.prURLTemplate, PRURLPlaceholder, url.PathEscape(pr.ID))
+	return fmt.Sprintf("<%s|%s>", link, title)
+}
+
+// chatName возвращает имя пользователя без упоминания
+func chatName(user *domain.User) string {
+	if user == nil {
+		return "unknown"
+	}
+	return chatEscaper.Replace(user.Username)
+}
+
+// chatMentions упоминает пользователей через chat_handle, а у кого он не задан - называет по имени.
+// chat_handle в неизвестном формате (например, сохранённый до проверки формата) экранируется
+func chatMentions(users []*domain.User) string {
+	if len(users) == 0 {
+		return "none"
+	}
+
+	mentions := make([]string, len(users))
+	for i, user := range users {
+		switch {
+		case domain.ValidChatHandle(user.ChatHandle):
+			mentions[i] = user.ChatHandle
+		case user.ChatHandle != "":
+			mentions[i] = chatEscaper.Replace(user.ChatHandle)
+		default:
+			mentions[i] = chatName(user)
+		}
+	}
+	return strings.Join(mentions, ", ")
+}
@@ -0,0 +1,129 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
+)
+
+// newChatServer возвращает сервер входящего вебхука, складывающий тексты сообщений в канал
+func newChatServer(t *testing.T, status int) (*httptest.Server, chan string) {
+	messages := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload struct {
+			Text string `json:"text"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		messages <- payload.Text
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, messages
+}
+
+func chatEvent(kind domain.PREventKind, webhookURL string) *domain.PREvent {
+	return &domain.PREvent{
+		Kind:   kind,
+		PR:     &domain.PullRequest{ID: "pr-1", Name: "Add <search>", AuthorID: "u1", TeamName: "backend"},
+		Team:   &domain.Team{Name: "backend", ChatWebhookURL: webhookURL},
+		Author: &domain.User{ID: "u1", Username: "Alice", ChatHandle: "<@U001>"},
+		Reviewers: []*domain.User{
+			{ID: "u2", Username: "Bob", ChatHandle: "<@U002>"},
+			{ID: "u3", Username: "Carol"},
+		},
+		OccurredAt: time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestChatNotifier_Assigned(t *testing.T) {
+	server, messages := newChatServer(t, http.StatusOK)
+
+	notifier := notify.NewChatNotifier("https://git.example.com/pulls/{id}", time.Second, zap.NewNop())
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), chatEvent(domain.PREventAssigned, server.URL)))
+
+	assert.Equal(t,
+		":eyes: Review requested: <https://git.example.com/pulls/pr-1|Add &lt;search&gt; (pr-1)> by Alice\n"+
+			"Reviewers: <@U002>, Carol",
+		<-messages)
+}
+
+func TestChatNotifier_ReassignedAndMerged(t *testing.T) {
+	server, messages := newChatServer(t, http.StatusOK)
+	notifier := notify.NewChatNotifier("", time.Second, zap.NewNop())
+
+	reassigned := chatEvent(domain.PREventReassigned, server.URL)
+	reassigned.Reviewers = reassigned.Reviewers[:1]
+	reassigned.Replaced = &domain.User{ID: "u4", Username: "Dave", ChatHandle: "<@U004>"}
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), reassigned))
+	assert.Equal(t,
+		":arrows_counterclockwise: Reviewer changed: *Add &lt;search&gt; (pr-1)* by Alice\n<@U002> replaces Dave",
+		<-messages)
+
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), chatEvent(domain.PREventMerged, server.URL)))
+	assert.Equal(t,
+		":white_check_mark: Merged: *Add &lt;search&gt; (pr-1)* by Alice\nReviewed by: Bob, Carol",
+		<-messages)
+}
+
+func TestChatNotifier_EscapesInvalidChatHandle(t *testing.T) {
+	server, messages := newChatServer(t, http.StatusOK)
+	notifier := notify.NewChatNotifier("", time.Second, zap.NewNop())
+
+	// chat_handle, сохранённый до проверки формата, не может добавить в сообщение разметку
+	event := chatEvent(domain.PREventAssigned, server.URL)
+	event.Reviewers = []*domain.User{
+		{ID: "u2", Username: "Bob", ChatHandle: "<!channel>&<https://evil.example|click>"},
+		{ID: "u3", Username: "Carol", ChatHandle: "@carol"},
+	}
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), event))
+	assert.Equal(t,
+		":eyes: Review requested: *Add &lt;search&gt; (pr-1)* by Alice\n"+
+			"Reviewers: &lt;!channel&gt;&amp;&lt;https://evil.example|click&gt;, @carol",
+		<-messages)
+}
+
+func TestChatNotifier_SkipsTeamsWithoutWebhook(t *testing.T) {
+	server, messages := newChatServer(t, http.StatusOK)
+	notifier := notify.NewChatNotifier("", time.Second, zap.NewNop())
+
+	noWebhook := chatEvent(domain.PREventAssigned, "")
+	assert.False(t, notifier.Wants(domain.PREventAssigned, noWebhook.Team))
+	assert.False(t, notifier.Wants(domain.PREventAssigned, nil))
+	assert.True(t, notifier.Wants(domain.PREventMerged, &domain.Team{ChatWebhookURL: server.URL}))
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), noWebhook))
+
+	noTeam := chatEvent(domain.PREventAssigned, server.URL)
+	noTeam.Team = nil
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), noTeam))
+
+	assert.Empty(t, messages)
+}
+
+func TestChatNotifier_DeliveryError(t *testing.T) {
+	server, _ := newChatServer(t, http.StatusNotFound)
+	notifier := notify.NewChatNotifier("", time.Second, zap.NewNop())
+
+	err := notifier.NotifyPREvent(context.Background(), chatEvent(domain.PREventAssigned, server.URL+"/hooks/secret-token"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+
+	// URL вебхука с токеном не попадает в ошибку, даже если сервер недоступен
+	server.Close()
+	err = notifier.NotifyPREvent(context.Background(), chatEvent(domain.PREventAssigned, server.URL+"/hooks/secret-token"))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
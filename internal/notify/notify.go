@@ -0,0 +1,80 @@
+// Package notify - отправка уведомлений пользователям во внешние системы
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// DigestNotifier отправляет пользователю сводку ожидающих его ревью
+type DigestNotifier interface {
+	SendDigest(ctx context.Context, digest *domain.ReviewDigest) error
+}
+
+// EventNotifier уведомляет о событиях PR: назначении и замене ревьюеров и мерже
+type EventNotifier interface {
+	// Wants сообщает, отправляет ли канал события kind для PR команды team (nil - PR без команды).
+	// Вызывается до чтения данных события, чтобы не собирать события, которые никуда не уйдут
+	Wants(kind domain.PREventKind, team *domain.Team) bool
+	NotifyPREvent(ctx context.Context, event *domain.PREvent) error
+}
+
+// PartialDeliveryError - уведомление отправлено не во все каналы. Err содержит ошибки неудачных каналов
+type PartialDeliveryError struct {
+	Err error
+}
+
+func (e *PartialDeliveryError) Error() string {
+	return "partial delivery: " + e.Err.Error()
+}
+
+func (e *PartialDeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// DigestNotifiers отправляет сводку через все каналы. Ошибка одного канала не мешает остальным.
+// Если хотя бы один канал отправил сводку, ошибки остальных возвращаются как *PartialDeliveryError.
+// Канал, пропустивший пользователя (например, без email), не возвращает ошибку
+type DigestNotifiers []DigestNotifier
+
+func (n DigestNotifiers) SendDigest(ctx context.Context, digest *domain.ReviewDigest) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.SendDigest(ctx, digest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch {
+	case len(errs) == 0:
+		return nil
+	case len(errs) < len(n):
+		return &PartialDeliveryError{Err: errors.Join(errs...)}
+	default:
+		return errors.Join(errs...)
+	}
+}
+
+// EventNotifiers уведомляет о событии через все каналы. Ошибка одного канала не мешает остальным
+type EventNotifiers []EventNotifier
+
+func (n EventNotifiers) Wants(kind domain.PREventKind, team *domain.Team) bool {
+	for _, notifier := range n {
+		if notifier.Wants(kind, team) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n EventNotifiers) NotifyPREvent(ctx context.Context, event *domain.PREvent) error {
+	var errs []error
+	for _, notifier := range n {
+		if notifier.Wants(event.Kind, event.Team) {
+			errs = append(errs, notifier.NotifyPREvent(ctx, event))
+		}
+	}
+	return errors.Join(errs...)
+}
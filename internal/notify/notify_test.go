@@ -0,0 +1,39 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
+)
+
+// digestFunc - канал сводок из функции
+type digestFunc func(ctx context.Context, digest *domain.ReviewDigest) error
+
+func (f digestFunc) SendDigest(ctx context.Context, digest *domain.ReviewDigest) error {
+	return f(ctx, digest)
+}
+
+func TestDigestNotifiers_PartialDelivery(t *testing.T) {
+	errDown := errors.New("channel down")
+	ok := digestFunc(func(context.Context, *domain.ReviewDigest) error { return nil })
+	failing := digestFunc(func(context.Context, *domain.ReviewDigest) error { return errDown })
+	digest := &domain.ReviewDigest{User: &domain.User{ID: "u1"}}
+
+	assert.NoError(t, notify.DigestNotifiers{ok, ok}.SendDigest(context.Background(), digest))
+
+	// Один канал доставил сводку - ошибка остальных частичная
+	err := notify.DigestNotifiers{ok, failing}.SendDigest(context.Background(), digest)
+	var partial *notify.PartialDeliveryError
+	assert.ErrorAs(t, err, &partial)
+	assert.ErrorIs(t, err, errDown)
+
+	// Не доставлено никуда
+	err = notify.DigestNotifiers{failing, failing}.SendDigest(context.Background(), digest)
+	assert.ErrorIs(t, err, errDown)
+	assert.False(t, errors.As(err, &partial))
+}
@@ -0,0 +1,330 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// SMTPTLSMode - способ шифрования соединения с SMTP-сервером
+type SMTPTLSMode string
+
+const (
+	SMTPTLSStartTLS SMTPTLSMode = "starttls" // STARTTLS обязателен
+	SMTPTLSImplicit SMTPTLSMode = "tls"      // TLS с начала соединения (обычно порт 465)
+	SMTPTLSNone     SMTPTLSMode = "none"     // без шифрования
+)
+
+func (m SMTPTLSMode) IsValid() bool {
+	switch m {
+	case SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+		return true
+	}
+	return false
+}
+
+// Имена шаблонов писем. Каждому соответствуют файлы <имя>.txt.tmpl и <имя>.html.tmpl,
+// тема письма задаётся шаблоном "subject" в текстовом файле
+const (
+	templatePREvent = "pr_event"
+	templateDigest  = "digest"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// SMTPConfig - параметры отправки писем
+type SMTPConfig struct {
+	Host         string
+	Port         int
+	Username     string // пусто - без аутентификации
+	Password     string
+	From         string // адрес отправителя, допускается имя: "Reviewer Bot <bot@example.com>"
+	TLS          SMTPTLSMode
+	Timeout      time.Duration // ограничивает отправку одного письма
+	TemplatesDir string        // пусто - встроенные шаблоны
+}
+
+// SMTPNotifier отправляет уведомления письмами на email пользователей.
+// Пользователи без email пропускаются
+type SMTPNotifier struct {
+	cfg       SMTPConfig
+	from      *mail.Address
+	templates map[string]*emailTemplate
+	logger    *zap.Logger
+}
+
+func NewSMTPNotifier(cfg SMTPConfig, logger *zap.Logger) (*SMTPNotifier, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+	if !cfg.TLS.IsValid() {
+		return nil, fmt.Errorf("unknown smtp tls mode: %s", cfg.TLS)
+	}
+
+	var fsys fs.FS = defaultTemplates
+	dir := "templates"
+	if cfg.TemplatesDir != "" {
+		fsys, dir = os.DirFS(cfg.TemplatesDir), "."
+	}
+
+	templates := make(map[string]*emailTemplate)
+	for _, name := range []string{templatePREvent, templateDigest} {
+		tmpl, err := loadEmailTemplate(fsys, dir, name)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = tmpl
+	}
+
+	return &SMTPNotifier{
+		cfg:       cfg,
+		from:      from,
+		templates: templates,
+		logger:    logger,
+	}, nil
+}
+
+// prEventEmail - данные шаблона письма о событии PR одному ревьюеру
+type prEventEmail struct {
+	Kind     domain.PREventKind
+	PR       *domain.PullRequest
+	Author   *domain.User
+	Reviewer *domain.User
+	Replaced *domain.User
+}
+
+// Wants сообщает, что письма отправляются о назначении и замене ревьюеров, но не о мерже PR
+func (n *SMTPNotifier) Wants(kind domain.PREventKind, _ *domain.Team) bool {
+	return kind == domain.PREventAssigned || kind == domain.PREventReassigned
+}
+
+// NotifyPREvent отправляет письмо каждому назначенному событием ревьюеру
+func (n *SMTPNotifier) NotifyPREvent(ctx context.Context, event *domain.PREvent) error {
+	if !n.Wants(event.Kind, event.Team) {
+		return nil
+	}
+
+	var errs []error
+	for _, reviewer := range event.Reviewers {
+		if reviewer.Email == "" {
+			continue
+		}
+
+		data := prEventEmail{
+			Kind:     event.Kind,
+			PR:       event.PR,
+			Author:   event.Author,
+			Reviewer: reviewer,
+			Replaced: event.Replaced,
+		}
+		if err := n.sendTemplate(ctx, reviewer, templatePREvent, data, event.OccurredAt); err != nil {
+			errs = append(errs, fmt.Errorf("email %s: %w", reviewer.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SendDigest отправляет сводку письмом
+func (n *SMTPNotifier) SendDigest(ctx context.Context, digest *domain.ReviewDigest) error {
+	if digest.User.Email == "" {
+		return nil
+	}
+	return n.sendTemplate(ctx, digest.User, templateDigest, digest, digest.GeneratedAt)
+}
+
+func (n *SMTPNotifier) sendTemplate(ctx context.Context, user *domain.User, name string, data any, date time.Time) error {
+	subject, text, html, err := n.templates[name].render(data)
+	if err != nil {
+		return err
+	}
+
+	to := &mail.Address{Name: user.Username, Address: user.Email}
+	msg, err := buildMessage(n.from, to, subject, text, html, date)
+	if err != nil {
+		return err
+	}
+
+	if err := n.send(ctx, to.Address, msg); err != nil {
+		return err
+	}
+
+	n.logger.Debug("email sent",
+		zap.String("user_id", user.ID),
+		zap.String("template", name),
+	)
+
+	return nil
+}
+
+// send доставляет письмо одному получателю
+func (n *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.cfg.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port)))
+	if err != nil {
+		return fmt.Errorf("dial smtp server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	tlsConfig := &tls.Config{ServerName: n.cfg.Host}
+	if n.cfg.TLS == SMTPTLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if n.cfg.TLS == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if n.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// emailTemplate - текстовая и HTML-версии письма
+type emailTemplate struct {
+	name string
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+func loadEmailTemplate(fsys fs.FS, dir, name string) (*emailTemplate, error) {
+	text, err := texttemplate.ParseFS(fsys, path.Join(dir, name+".txt.tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s text template: %w", name, err)
+	}
+	if text.Lookup("subject") == nil {
+		return nil, fmt.Errorf("%s text template does not define subject", name)
+	}
+
+	html, err := htmltemplate.ParseFS(fsys, path.Join(dir, name+".html.tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s html template: %w", name, err)
+	}
+
+	return &emailTemplate{name: name, text: text, html: html}, nil
+}
+
+func (t *emailTemplate) render(data any) (subject, text, html string, err error) {
+	var buf bytes.Buffer
+	if err := t.text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", "", fmt.Errorf("render %s subject: %w", t.name, err)
+	}
+	// Перевод строки в теме позволил бы внедрить в письмо свои заголовки
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := t.text.ExecuteTemplate(&buf, t.name+".txt.tmpl", data); err != nil {
+		return "", "", "", fmt.Errorf("render %s text: %w", t.name, err)
+	}
+	text = buf.String()
+
+	buf.Reset()
+	if err := t.html.ExecuteTemplate(&buf, t.name+".html.tmpl", data); err != nil {
+		return "", "", "", fmt.Errorf("render %s html: %w", t.name, err)
+	}
+	html = buf.String()
+
+	return subject, text, html, nil
+}
+
+// buildMessage собирает письмо multipart/alternative с текстовой и HTML-версиями
+func buildMessage(from, to *mail.Address, subject, text, html string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create message part: %w", err)
+		}
+
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, fmt.Errorf("encode message part: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("encode message part: %w", err)
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close message: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,321 @@
+package notify_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
+)
+
+// receivedMail - письмо, принятое fakeSMTPServer
+type receivedMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// fakeSMTPServer - минимальный SMTP-сервер без шифрования и аутентификации.
+// Адреса из rejectRcpt отклоняются ответом 550
+type fakeSMTPServer struct {
+	listener   net.Listener
+	rejectRcpt map[string]bool
+	mails      chan receivedMail
+}
+
+func newFakeSMTPServer(t *testing.T, rejectRcpt ...string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeSMTPServer{
+		listener:   listener,
+		rejectRcpt: make(map[string]bool),
+		mails:      make(chan receivedMail, 10),
+	}
+	for _, addr := range rejectRcpt {
+		s.rejectRcpt[addr] = true
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	var mail receivedMail
+	_ = tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch cmd {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250-localhost")
+			_ = tp.PrintfLine("250 8BITMIME")
+		case "MAIL":
+			mail = receivedMail{from: smtpPath(line)}
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			to := smtpPath(line)
+			if s.rejectRcpt[to] {
+				_ = tp.PrintfLine("550 no such user")
+				continue
+			}
+			mail.to = append(mail.to, to)
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.data = data
+			s.mails <- mail
+			_ = tp.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 OK")
+		}
+	}
+}
+
+// smtpPath извлекает адрес из "MAIL FROM:<addr>" / "RCPT TO:<addr>"
+func smtpPath(line string) string {
+	start, end := strings.Index(line, "<"), strings.Index(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func (s *fakeSMTPServer) config(t *testing.T) notify.SMTPConfig {
+	host, port, err := net.SplitHostPort(s.listener.Addr().String())
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return notify.SMTPConfig{
+		Host:    host,
+		Port:    portNum,
+		From:    "Reviewer Bot <bot@example.com>",
+		TLS:     notify.SMTPTLSNone,
+		Timeout: 5 * time.Second,
+	}
+}
+
+func (s *fakeSMTPServer) receive(t *testing.T) receivedMail {
+	select {
+	case m := <-s.mails:
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+		return receivedMail{}
+	}
+}
+
+// parsedMail - тема и части письма multipart/alternative по Content-Type
+type parsedMail struct {
+	header  mail.Header
+	subject string
+	parts   map[string]string
+}
+
+func parseMail(t *testing.T, data []byte) parsedMail {
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		parts[partType] = string(body)
+	}
+
+	return parsedMail{header: msg.Header, subject: subject, parts: parts}
+}
+
+func TestSMTPNotifier_NotifyPREvent(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	notifier, err := notify.NewSMTPNotifier(server.config(t), zap.NewNop())
+	require.NoError(t, err)
+
+	// О мерже письма не отправляются, команда PR не важна
+	assert.True(t, notifier.Wants(domain.PREventAssigned, nil))
+	assert.True(t, notifier.Wants(domain.PREventReassigned, nil))
+	assert.False(t, notifier.Wants(domain.PREventMerged, &domain.Team{Name: "backend"}))
+
+	event := &domain.PREvent{
+		Kind:   domain.PREventReassigned,
+		PR:     &domain.PullRequest{ID: "pr-1", Name: "Add <search> «filters»", AuthorID: "u1", TeamName: "backend"},
+		Author: &domain.User{ID: "u1", Username: "Alice"},
+		Reviewers: []*domain.User{
+			{ID: "u2", Username: "Bob", Email: "bob@example.com"},
+			{ID: "u3", Username: "Carol"}, // без email - пропускается
+		},
+		Replaced:   &domain.User{ID: "u4", Username: "Dave"},
+		OccurredAt: time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), event))
+
+	received := server.receive(t)
+	assert.Equal(t, "bot@example.com", received.from)
+	assert.Equal(t, []string{"bob@example.com"}, received.to)
+
+	msg := parseMail(t, received.data)
+	assert.Equal(t, "Review reassigned to you: Add <search> «filters»", msg.subject)
+	assert.Contains(t, msg.header.Get("To"), "bob@example.com")
+
+	text := msg.parts["text/plain"]
+	assert.Contains(t, text, "Hi Bob")
+	assert.Contains(t, text, "You replaced Dave")
+	assert.Contains(t, text, `pr-1 "Add <search> «filters»" by Alice`)
+	assert.Contains(t, text, "Team: backend")
+
+	html := msg.parts["text/html"]
+	assert.Contains(t, html, "Add &lt;search&gt;")
+	assert.NotContains(t, html, "<search>")
+
+	select {
+	case extra := <-server.mails:
+		t.Fatalf("unexpected mail to %v", extra.to)
+	default:
+	}
+}
+
+func TestSMTPNotifier_SendDigest(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	notifier, err := notify.NewSMTPNotifier(server.config(t), zap.NewNop())
+	require.NoError(t, err)
+
+	digest := &domain.ReviewDigest{
+		User: &domain.User{ID: "u2", Username: "Bob", Email: "bob@example.com"},
+		PullRequests: []*domain.PullRequest{
+			{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+			{ID: "pr-2", Name: "Fix login", AuthorID: "u3"},
+		},
+		GeneratedAt: time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, notifier.SendDigest(context.Background(), digest))
+
+	msg := parseMail(t, server.receive(t).data)
+	assert.Equal(t, "2 pull request(s) awaiting your review", msg.subject)
+	assert.Contains(t, msg.parts["text/plain"], `- pr-1 "Add search" by u1`)
+	assert.Contains(t, msg.parts["text/plain"], `- pr-2 "Fix login" by u3`)
+
+	// Пользователю без email сводка не отправляется
+	digest.User.Email = ""
+	require.NoError(t, notifier.SendDigest(context.Background(), digest))
+	select {
+	case <-server.mails:
+		t.Fatal("digest sent to user without email")
+	default:
+	}
+}
+
+func TestSMTPNotifier_RecipientRejected(t *testing.T) {
+	server := newFakeSMTPServer(t, "bob@example.com")
+
+	notifier, err := notify.NewSMTPNotifier(server.config(t), zap.NewNop())
+	require.NoError(t, err)
+
+	event := &domain.PREvent{
+		Kind:      domain.PREventAssigned,
+		PR:        &domain.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+		Author:    &domain.User{ID: "u1", Username: "Alice"},
+		Reviewers: []*domain.User{{ID: "u2", Username: "Bob", Email: "bob@example.com"}},
+	}
+
+	err = notifier.NotifyPREvent(context.Background(), event)
+	assert.ErrorContains(t, err, "550")
+}
+
+func TestSMTPNotifier_CustomTemplates(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	dir := t.TempDir()
+	templates := map[string]string{
+		"pr_event.txt.tmpl":  `{{define "subject"}}[{{.PR.ID}}] {{.Kind}}{{end}}Custom text for {{.Reviewer.Username}}`,
+		"pr_event.html.tmpl": `<p>Custom html for {{.Reviewer.Username}}</p>`,
+		"digest.txt.tmpl":    `{{define "subject"}}Digest{{end}}Digest`,
+		"digest.html.tmpl":   `<p>Digest</p>`,
+	}
+	for name, content := range templates {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	cfg := server.config(t)
+	cfg.TemplatesDir = dir
+	notifier, err := notify.NewSMTPNotifier(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	event := &domain.PREvent{
+		Kind:      domain.PREventAssigned,
+		PR:        &domain.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+		Author:    &domain.User{ID: "u1", Username: "Alice"},
+		Reviewers: []*domain.User{{ID: "u2", Username: "Bob", Email: "bob@example.com"}},
+	}
+	require.NoError(t, notifier.NotifyPREvent(context.Background(), event))
+
+	msg := parseMail(t, server.receive(t).data)
+	assert.Equal(t, "[pr-1] assigned", msg.subject)
+	assert.Equal(t, "Custom text for Bob", msg.parts["text/plain"])
+	assert.Equal(t, "<p>Custom html for Bob</p>", msg.parts["text/html"])
+
+	// Шаблон без темы отклоняется при создании
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "digest.txt.tmpl"), []byte("Digest"), 0o600))
+	_, err = notify.NewSMTPNotifier(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
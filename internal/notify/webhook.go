@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// EventReviewDigest - тип события со сводкой ожидающих ревью
+const EventReviewDigest = "review_digest"
+
+// WebhookNotifier отправляет уведомления POST-запросом с JSON на заданный URL
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+	logger *zap.Logger
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration, logger *zap.Logger) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		logger: logger,
+	}
+}
+
+// digestPayload - тело запроса со сводкой
+type digestPayload struct {
+	Event        string            `json:"event"`
+	UserID       string            `json:"user_id"`
+	Username     string            `json:"username"`
+	PullRequests []digestPRPayload `json:"pull_requests"`
+	GeneratedAt  time.Time         `json:"generated_at"`
+}
+
+type digestPRPayload struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+}
+
+// SendDigest отправляет сводку. Ответ со статусом не 2xx считается ошибкой
+func (n *WebhookNotifier) SendDigest(ctx context.Context, digest *domain.ReviewDigest) error {
+	payload := digestPayload{
+		Event:        EventReviewDigest,
+		UserID:       digest.User.ID,
+		Username:     digest.User.Username,
+		PullRequests: make([]digestPRPayload, len(digest.PullRequests)),
+		GeneratedAt:  digest.GeneratedAt,
+	}
+	for i, pr := range digest.PullRequests {
+		payload.PullRequests[i] = digestPRPayload{
+			PullRequestID:   pr.ID,
+			PullRequestName: pr.Name,
+			AuthorID:        pr.AuthorID,
+		}
+	}
+
+	return postJSON(ctx, n.client, n.url, payload, n.logger)
+}
+
+// postJSON отправляет payload POST-запросом на target. Ответ со статусом не 2xx считается ошибкой
+func postJSON(ctx context.Context, client *http.Client, target string, payload any, logger *zap.Logger) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// URL вебхука часто содержит токен: в ошибку, которая попадёт в лог, он не включается
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	logger.Debug("webhook delivered", zap.Int("status", resp.StatusCode))
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
+)
+
+func TestWebhookNotifier_SendDigest(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	digest := &domain.ReviewDigest{
+		User: &domain.User{ID: "u2", Username: "Bob"},
+		PullRequests: []*domain.PullRequest{
+			{ID: "pr-1", Name: "Add search", AuthorID: "u1", Status: domain.StatusOpen},
+		},
+		GeneratedAt: time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+	}
+
+	notifier := notify.NewWebhookNotifier(server.URL, time.Second, zap.NewNop())
+	require.NoError(t, notifier.SendDigest(context.Background(), digest))
+
+	assert.Equal(t, notify.EventReviewDigest, payload["event"])
+	assert.Equal(t, "u2", payload["user_id"])
+	assert.Equal(t, "2025-07-02T09:00:00Z", payload["generated_at"])
+	assert.Equal(t, []any{map[string]any{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add search",
+		"author_id":         "u1",
+	}}, payload["pull_requests"])
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(server.URL, time.Second, zap.NewNop())
+	err := notifier.SendDigest(context.Background(), &domain.ReviewDigest{User: &domain.User{ID: "u2"}})
+	assert.ErrorContains(t, err, "502")
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// AbsenceRepository хранит периоды отсутствия пользователей
+type AbsenceRepository interface {
+	Create(ctx context.Context, absence *domain.Absence) error
+	// Delete удаляет отсутствие пользователя и возвращает его. Если записи нет, возвращается ErrNotFound
+	Delete(ctx context.Context, userID string, absenceID int64) (*domain.Absence, error)
+	// ListByUserID возвращает отсутствия пользователя, которые заканчиваются после from
+	ListByUserID(ctx context.Context, userID string, from time.Time) ([]*domain.Absence, error)
+	// ListByTeamID возвращает отсутствия участников команды, пересекающиеся с [from, to)
+	ListByTeamID(ctx context.Context, teamID int, from, to time.Time) ([]*domain.Absence, error)
+}
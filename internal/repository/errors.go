@@ -3,7 +3,12 @@ package repository
 import "errors"
 
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrAlreadyExists = errors.New("already exists")
-	ErrConflict      = errors.New("conflict")
+	ErrNotFound        = errors.New("not found")
+	ErrAlreadyExists   = errors.New("already exists")
+	ErrConflict        = errors.New("conflict")
+	ErrVersionMismatch = errors.New("version mismatch")
+	ErrUsernameTaken   = errors.New("username already taken")
+	ErrParentNotFound  = errors.New("parent team not found")
+	ErrHierarchyCycle  = errors.New("team hierarchy cycle")
+	ErrAtCapacity      = errors.New("reviewer at open review limit")
 )
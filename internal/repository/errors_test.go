@@ -15,6 +15,11 @@ func TestErrors(t *testing.T) {
 	}{
 		{"ErrNotFound", repository.ErrNotFound},
 		{"ErrAlreadyExists", repository.ErrAlreadyExists},
+		{"ErrVersionMismatch", repository.ErrVersionMismatch},
+		{"ErrUsernameTaken", repository.ErrUsernameTaken},
+		{"ErrParentNotFound", repository.ErrParentNotFound},
+		{"ErrHierarchyCycle", repository.ErrHierarchyCycle},
+		{"ErrAtCapacity", repository.ErrAtCapacity},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// IdempotencyRepository хранит ответы на запросы с Idempotency-Key
+type IdempotencyRepository interface {
+	// Reserve резервирует ключ. Если ключ уже занят и не просрочен, возвращает существующую запись
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key, scope string, statusCode int, etag string, body []byte) error
+	Delete(ctx context.Context, key, scope string) error
+	// DeleteExpired удаляет просроченные ключи и возвращает их число
+	DeleteExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// JobRepository хранит историю запусков фоновых задач и блокировки,
+// не дающие выполнять одну задачу на нескольких экземплярах сервиса одновременно
+type JobRepository interface {
+	// TryLock берёт блокировку задачи. Если блокировку держит другой запуск, возвращает ok = false.
+	// Блокировка держится до вызова unlock или до разрыва соединения с БД
+	TryLock(ctx context.Context, jobName string) (unlock func(), ok bool, err error)
+	// StartRun сохраняет новый запуск и помечает failed прерванные запуски задачи.
+	// Вызывается под блокировкой задачи
+	StartRun(ctx context.Context, run *domain.JobRun) error
+	FinishRun(ctx context.Context, run *domain.JobRun) error
+	// LastRuns возвращает последний запуск каждой задачи по её имени
+	LastRuns(ctx context.Context) (map[string]*domain.JobRun, error)
+	// ListRuns возвращает последние limit запусков задачи, новые первыми
+	ListRuns(ctx context.Context, jobName string, limit int) ([]*domain.JobRun, error)
+}
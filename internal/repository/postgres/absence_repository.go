@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+)
+
+type AbsenceRepository struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewAbsenceRepository(pool *pgxpool.Pool, logger *zap.Logger) *AbsenceRepository {
+	return &AbsenceRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+// Create сохраняет период отсутствия пользователя
+func (r *AbsenceRepository) Create(ctx context.Context, absence *domain.Absence) error {
+	query := `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query,
+		absence.UserID,
+		absence.StartsAt,
+		absence.EndsAt,
+		absence.Reason,
+		absence.CreatedAt,
+	).Scan(&absence.ID)
+
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return repository.ErrNotFound
+		}
+		r.logger.Error("failed to create absence",
+			zap.String("user_id", absence.UserID),
+			zap.Error(err),
+		)
+		return fmt.Errorf("create absence: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет период отсутствия пользователя
+func (r *AbsenceRepository) Delete(ctx context.Context, userID string, absenceID int64) (*domain.Absence, error) {
+	query := `
+		DELETE FROM user_absences
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, user_id, starts_at, ends_at, reason, created_at
+	`
+
+	var absence domain.Absence
+	err := r.pool.QueryRow(ctx, query, absenceID, userID).Scan(
+		&absence.ID,
+		&absence.UserID,
+		&absence.StartsAt,
+		&absence.EndsAt,
+		&absence.Reason,
+		&absence.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		r.logger.Error("failed to delete absence",
+			zap.String("user_id", userID),
+			zap.Int64("absence_id", absenceID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("delete absence: %w", err)
+	}
+
+	return &absence, nil
+}
+
+// ListByUserID возвращает текущие и будущие отсутствия пользователя
+func (r *AbsenceRepository) ListByUserID(ctx context.Context, userID string, from time.Time) ([]*domain.Absence, error) {
+	query := `
+		SELECT id, user_id, starts_at, ends_at, reason, created_at
+		FROM user_absences
+		WHERE user_id = $1 AND ends_at > $2
+		ORDER BY starts_at, id
+	`
+
+	absences, err := r.query(ctx, query, userID, from)
+	if err != nil {
+		r.logger.Error("failed to list user absences",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list user absences: %w", err)
+	}
+
+	return absences, nil
+}
+
+// ListByTeamID возвращает отсутствия участников команды в интервале [from, to)
+func (r *AbsenceRepository) ListByTeamID(ctx context.Context, teamID int, from, to time.Time) ([]*domain.Absence, error) {
+	query := `
+		SELECT a.id, a.user_id, a.starts_at, a.ends_at, a.reason, a.created_at
+		FROM user_absences a
+		INNER JOIN team_memberships m ON m.user_id = a.user_id
+		WHERE m.team_id = $1
+		  AND a.ends_at > $2
+		  AND a.starts_at < $3
+		ORDER BY a.starts_at, a.user_id, a.id
+	`
+
+	absences, err := r.query(ctx, query, teamID, from, to)
+	if err != nil {
+		r.logger.Error("failed to list team absences",
+			zap.Int("team_id", teamID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list team absences: %w", err)
+	}
+
+	return absences, nil
+}
+
+func (r *AbsenceRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Absence, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	absences := []*domain.Absence{}
+	for rows.Next() {
+		var absence domain.Absence
+		if err := rows.Scan(
+			&absence.ID,
+			&absence.UserID,
+			&absence.StartsAt,
+			&absence.EndsAt,
+			&absence.Reason,
+			&absence.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan absence: %w", err)
+		}
+		absences = append(absences, &absence)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate absences: %w", err)
+	}
+
+	return absences, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+)
+
+type IdempotencyRepository struct {
+	pool      *pgxpool.Pool
+	txManager *TxManager
+	logger    *zap.Logger
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool, txManager *TxManager, logger *zap.Logger) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		pool:      pool,
+		txManager: txManager,
+		logger:    logger,
+	}
+}
+
+// Reserve резервирует ключ или возвращает уже существующую запись
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	var existing *domain.IdempotencyRecord
+
+	err := r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		// Просроченный ключ можно использовать заново, как и ключ, запрос по которому
+		// так и не завершился (например, процесс упал во время обработки)
+		deleteQuery := `
+			DELETE FROM idempotency_keys
+			WHERE key = $1 AND scope = $2
+			  AND (expires_at < now() OR (status_code IS NULL AND locked_until < now()))
+		`
+
+		if _, err := tx.Exec(ctx, deleteQuery, record.Key, record.Scope); err != nil {
+			return fmt.Errorf("delete expired key: %w", err)
+		}
+
+		insertQuery := `
+			INSERT INTO idempotency_keys (key, scope, request_hash, created_at, expires_at, locked_until)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (key, scope) DO NOTHING
+		`
+
+		result, err := tx.Exec(ctx, insertQuery,
+			record.Key,
+			record.Scope,
+			record.RequestHash,
+			record.CreatedAt,
+			record.ExpiresAt,
+			record.LockedUntil,
+		)
+		if err != nil {
+			return fmt.Errorf("insert idempotency key: %w", err)
+		}
+
+		if result.RowsAffected() > 0 {
+			return nil
+		}
+
+		// Ключ уже занят другим запросом
+		selectQuery := `
+			SELECT key, scope, request_hash, COALESCE(status_code, 0), response_body, COALESCE(etag, ''), created_at, expires_at, locked_until
+			FROM idempotency_keys
+			WHERE key = $1 AND scope = $2
+		`
+
+		var rec domain.IdempotencyRecord
+		err = tx.QueryRow(ctx, selectQuery, record.Key, record.Scope).Scan(
+			&rec.Key,
+			&rec.Scope,
+			&rec.RequestHash,
+			&rec.StatusCode,
+			&rec.ResponseBody,
+			&rec.ETag,
+			&rec.CreatedAt,
+			&rec.ExpiresAt,
+			&rec.LockedUntil,
+		)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrConflict
+			}
+			return fmt.Errorf("get idempotency key: %w", err)
+		}
+
+		existing = &rec
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error("failed to reserve idempotency key",
+			zap.String("key", record.Key),
+			zap.String("scope", record.Scope),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Complete сохраняет ответ для зарезервированного ключа
+func (r *IdempotencyRepository) Complete(ctx context.Context, key, scope string, statusCode int, etag string, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, etag = NULLIF($4, ''), response_body = $5
+		WHERE key = $1 AND scope = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query, key, scope, statusCode, etag, body)
+	if err != nil {
+		r.logger.Error("failed to save idempotent response",
+			zap.String("key", key),
+			zap.String("scope", scope),
+			zap.Error(err),
+		)
+		return fmt.Errorf("save idempotent response: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete освобождает ключ, чтобы запрос можно было повторить
+func (r *IdempotencyRepository) Delete(ctx context.Context, key, scope string) error {
+	query := `DELETE FROM idempotency_keys WHERE key = $1 AND scope = $2`
+
+	if _, err := r.pool.Exec(ctx, query, key, scope); err != nil {
+		r.logger.Error("failed to delete idempotency key",
+			zap.String("key", key),
+			zap.String("scope", scope),
+			zap.Error(err),
+		)
+		return fmt.Errorf("delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет просроченные ключи
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at < now()`
+
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to delete expired idempotency keys", zap.Error(err))
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// jobLockClassID - первый ключ advisory-блокировок фоновых задач, второй - hashtext(имя задачи).
+// Отделяет блокировки задач от других advisory-блокировок в той же БД
+const jobLockClassID = 0x6a6f62 // "job"
+
+// unlockTimeout ограничивает снятие блокировки, когда контекст задачи уже отменён
+const unlockTimeout = 5 * time.Second
+
+type JobRepository struct {
+	pool      *pgxpool.Pool
+	txManager *TxManager
+	logger    *zap.Logger
+}
+
+func NewJobRepository(pool *pgxpool.Pool, txManager *TxManager, logger *zap.Logger) *JobRepository {
+	return &JobRepository{
+		pool:      pool,
+		txManager: txManager,
+		logger:    logger,
+	}
+}
+
+// TryLock берёт сессионную advisory-блокировку задачи на отдельном соединении.
+// Соединение возвращается в пул только после снятия блокировки
+func (r *JobRepository) TryLock(ctx context.Context, jobName string) (func(), bool, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	var locked bool
+	err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1, hashtext($2))`, jobLockClassID, jobName).Scan(&locked)
+	if err != nil {
+		conn.Release()
+		r.logger.Error("failed to lock job",
+			zap.String("job_name", jobName),
+			zap.Error(err),
+		)
+		return nil, false, fmt.Errorf("lock job: %w", err)
+	}
+
+	if !locked {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		unlockCtx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1, hashtext($2))`, jobLockClassID, jobName); err != nil {
+			// Блокировка снимается вместе с сессией: закрываем соединение, чтобы оно не вернулось в пул
+			r.logger.Error("failed to unlock job, closing connection",
+				zap.String("job_name", jobName),
+				zap.Error(err),
+			)
+			_ = conn.Conn().Close(unlockCtx)
+		}
+		conn.Release()
+	}
+
+	return unlock, true, nil
+}
+
+// StartRun сохраняет запуск задачи в статусе running
+func (r *JobRepository) StartRun(ctx context.Context, run *domain.JobRun) error {
+	return r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		// Запуски, оставшиеся в running после остановки экземпляра, уже не завершатся
+		interruptQuery := `
+			UPDATE job_runs
+			SET status = 'failed', error = 'interrupted', finished_at = $2
+			WHERE job_name = $1 AND status = 'running'
+		`
+
+		if _, err := tx.Exec(ctx, interruptQuery, run.JobName, run.StartedAt); err != nil {
+			return fmt.Errorf("mark interrupted runs: %w", err)
+		}
+
+		insertQuery := `
+			INSERT INTO job_runs (job_name, trigger, status, started_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`
+
+		err := tx.QueryRow(ctx, insertQuery, run.JobName, run.Trigger, run.Status, run.StartedAt).Scan(&run.ID)
+		if err != nil {
+			r.logger.Error("failed to start job run",
+				zap.String("job_name", run.JobName),
+				zap.Error(err),
+			)
+			return fmt.Errorf("insert job run: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// FinishRun сохраняет результат запуска
+func (r *JobRepository) FinishRun(ctx context.Context, run *domain.JobRun) error {
+	query := `
+		UPDATE job_runs
+		SET status = $2, processed = $3, error = NULLIF($4, ''), finished_at = $5
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, run.ID, run.Status, run.Processed, run.Error, run.FinishedAt)
+	if err != nil {
+		r.logger.Error("failed to finish job run",
+			zap.String("job_name", run.JobName),
+			zap.Int64("run_id", run.ID),
+			zap.Error(err),
+		)
+		return fmt.Errorf("update job run: %w", err)
+	}
+
+	return nil
+}
+
+// LastRuns возвращает последний запуск каждой задачи
+func (r *JobRepository) LastRuns(ctx context.Context) (map[string]*domain.JobRun, error) {
+	query := `
+		SELECT DISTINCT ON (job_name)
+			id, job_name, trigger, status, processed, COALESCE(error, ''), started_at, finished_at
+		FROM job_runs
+		ORDER BY job_name, started_at DESC, id DESC
+	`
+
+	runs, err := r.queryRuns(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*domain.JobRun, len(runs))
+	for _, run := range runs {
+		byName[run.JobName] = run
+	}
+
+	return byName, nil
+}
+
+// ListRuns возвращает последние запуски задачи
+func (r *JobRepository) ListRuns(ctx context.Context, jobName string, limit int) ([]*domain.JobRun, error) {
+	query := `
+		SELECT id, job_name, trigger, status, processed, COALESCE(error, ''), started_at, finished_at
+		FROM job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2
+	`
+
+	return r.queryRuns(ctx, query, jobName, limit)
+}
+
+func (r *JobRepository) queryRuns(ctx context.Context, query string, args ...any) ([]*domain.JobRun, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to query job runs", zap.Error(err))
+		return nil, fmt.Errorf("query job runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []*domain.JobRun{}
+	for rows.Next() {
+		var run domain.JobRun
+		if err := rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.Trigger,
+			&run.Status,
+			&run.Processed,
+			&run.Error,
+			&run.StartedAt,
+			&run.FinishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan job run: %w", err)
+		}
+		runs = append(runs, &run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate job runs: %w", err)
+	}
+
+	return runs, nil
+}
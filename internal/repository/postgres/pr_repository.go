@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,6 +16,9 @@ import (
 	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
 )
 
+// reviewCapacityLockClassID - первый ключ advisory-блокировок лимита открытых ревью, второй - hashtext(ID пользователя)
+const reviewCapacityLockClassID = 0x726576 // "rev"
+
 type PullRequestRepository struct {
 	pool      *pgxpool.Pool
 	txManager *TxManager
@@ -33,14 +38,15 @@ func (r PullRequestRepository) Create(ctx context.Context, pr *domain.PullReques
 	return r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
 		// Создаем PR
 		prQuery := `
-			INSERT INTO pull_requests (id, name, author_id, status_id, created_at)
-			VALUES ($1, $2, $3, (SELECT id FROM pr_statuses WHERE name = $4), $5)
+			INSERT INTO pull_requests (id, name, author_id, team_id, status_id, created_at)
+			VALUES ($1, $2, $3, NULLIF($4, 0), (SELECT id FROM pr_statuses WHERE name = $5), $6)
 		`
 
 		_, err := tx.Exec(ctx, prQuery,
 			pr.ID,
 			pr.Name,
 			pr.AuthorID,
+			pr.TeamID,
 			pr.Status,
 			pr.CreatedAt,
 		)
@@ -60,6 +66,10 @@ func (r PullRequestRepository) Create(ctx context.Context, pr *domain.PullReques
 
 		// Назначаем ревьюеров
 		if len(reviewerIDs) > 0 {
+			if err := reserveReviewCapacity(ctx, tx, reviewerIDs); err != nil {
+				return err
+			}
+
 			reviewerQuery := `
 			INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at)
 			VALUES ($1, $2, now()) 
@@ -89,15 +99,19 @@ func (r PullRequestRepository) GetByID(ctx context.Context, id string) (*domain.
 		    pr.id,
 		    pr.name,
 		    pr.author_id,
+		    COALESCE(pr.team_id, 0) as team_id,
+		    COALESCE(t.name, '') as team_name,
 		    ps.name as status,
 		    pr.created_at,
 		    pr.merged_at,
+		    pr.version,
 		    COALESCE(array_agg(rev.user_id) FILTER ( WHERE rev.user_id IS NOT NULL ), '{}') as reviewers
 		FROM pull_requests pr
 		INNER JOIN pr_statuses ps ON pr.status_id = ps.id
+		LEFT JOIN teams t ON t.id = pr.team_id
 		LEFT JOIN pr_reviewers rev ON rev.pull_request_id = pr.id
 		WHERE pr.id = $1
-		GROUP BY pr.id, ps.name, pr.id, pr.name, pr.author_id, ps.name, pr.created_at, pr.merged_at
+		GROUP BY pr.id, ps.name, t.name
 	`
 
 	var pr domain.PullRequest
@@ -105,9 +119,12 @@ func (r PullRequestRepository) GetByID(ctx context.Context, id string) (*domain.
 		&pr.ID,
 		&pr.Name,
 		&pr.AuthorID,
+		&pr.TeamID,
+		&pr.TeamName,
 		&pr.Status,
 		&pr.CreatedAt,
 		&pr.MergedAt,
+		&pr.Version,
 		&pr.AssignedReviewers,
 	)
 
@@ -125,67 +142,67 @@ func (r PullRequestRepository) GetByID(ctx context.Context, id string) (*domain.
 	return &pr, nil
 }
 
-// UpdateStatus обновляет статус PR, если он в статусе OPEN
-func (r PullRequestRepository) UpdateStatus(ctx context.Context, id string, status string, mergedAt *time.Time) error {
+// UpdateStatus обновляет статус PR, если он в статусе OPEN, и сообщает, изменился ли статус
+func (r PullRequestRepository) UpdateStatus(ctx context.Context, id string, status string, mergedAt *time.Time, expectedVersion int) (bool, error) {
 	query := `
 		UPDATE pull_requests
 		SET 
 		    status_id = (SELECT id FROM pr_statuses WHERE name = $2),
-		    merged_at = $3
-		WHERE id = $1 AND status_id = (SELECT id FROM pr_statuses WHERE name = 'OPEN')
+		    merged_at = $3,
+		    version = version + 1
+		WHERE id = $1
+		  AND status_id = (SELECT id FROM pr_statuses WHERE name = 'OPEN')
+		  AND ($4 = 0 OR version = $4)
 	`
 
-	result, err := r.pool.Exec(ctx, query, id, status, mergedAt)
+	result, err := r.pool.Exec(ctx, query, id, status, mergedAt, expectedVersion)
 	if err != nil {
 		r.logger.Error("failed to update PR status",
 			zap.String("pr_id", id),
 			zap.Error(err),
 		)
-		return fmt.Errorf("update PR status: %w", err)
+		return false, fmt.Errorf("update PR status: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
 		// Проверяем существование PR
-		existsQuery := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = $1)`
-		var exists bool
-		err := r.pool.QueryRow(ctx, existsQuery, id).Scan(&exists)
-		if err != nil {
-			return fmt.Errorf("check PR exists: %w", err)
-		}
-
-		if !exists {
-			return repository.ErrNotFound
-		}
-
-		// PR существует, но уже не в статусе OPEN (идемпотентность - ничего не делаем)
-		return nil
-	}
-
-	return nil
-}
-
-// ReplaceReviewer заменяет одного ревьюера на другого
-func (r PullRequestRepository) ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) error {
-	return r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
-		// Проверяем, что PR в статусе OPEN
 		statusQuery := `
-			SELECT ps.name
+			SELECT ps.name, pr.version
 			FROM pull_requests pr
 			INNER JOIN pr_statuses ps ON pr.status_id = ps.id
 			WHERE pr.id = $1
 		`
 
-		var status string
-		err := tx.QueryRow(ctx, statusQuery, prID).Scan(&status)
+		var (
+			currentStatus  string
+			currentVersion int
+		)
+		err := r.pool.QueryRow(ctx, statusQuery, id).Scan(&currentStatus, &currentVersion)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
-				return repository.ErrNotFound
+				return false, repository.ErrNotFound
 			}
-			return fmt.Errorf("check PR status: %w", err)
+			return false, fmt.Errorf("check PR exists: %w", err)
+		}
+
+		// PR открыт, значит не совпала версия. Смерженный параллельным запросом PR
+		// тоже не совпадает с ожидаемой версией: его версия уже увеличена
+		if currentStatus == domain.StatusOpen || (expectedVersion != 0 && currentVersion != expectedVersion) {
+			return false, repository.ErrVersionMismatch
 		}
 
-		if status != domain.StatusOpen {
-			return fmt.Errorf("PR is %s: %w", status, repository.ErrConflict)
+		// PR существует, но уже не в статусе OPEN (идемпотентность - ничего не делаем)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// ReplaceReviewer заменяет одного ревьюера на другого и записывает снятие и назначение в журнал
+func (r PullRequestRepository) ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID, actorID string, expectedVersion int) error {
+	return r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		if err := lockOpenPR(ctx, tx, prID, expectedVersion); err != nil {
+			return err
 		}
 
 		// Удаляем старого ревьюера
@@ -205,6 +222,10 @@ func (r PullRequestRepository) ReplaceReviewer(ctx context.Context, prID, oldUse
 		}
 
 		// Добавляем нового ревьюера
+		if err := reserveReviewCapacity(ctx, tx, []string{newUserID}); err != nil {
+			return err
+		}
+
 		insertQuery := `
 			INSERT INTO pr_reviewers(pull_request_id, user_id, assigned_at)
 			VALUES ($1, $2, now())
@@ -220,10 +241,327 @@ func (r PullRequestRepository) ReplaceReviewer(ctx context.Context, prID, oldUse
 			return fmt.Errorf("insert new reviewer: %w", err)
 		}
 
+		if err := logReviewerChange(ctx, tx, prID, oldUserID, domain.ReviewerChangeRemoved, actorID); err != nil {
+			return err
+		}
+		if err := logReviewerChange(ctx, tx, prID, newUserID, domain.ReviewerChangeAdded, actorID); err != nil {
+			return err
+		}
+
+		return bumpPRVersion(ctx, tx, prID)
+	})
+}
+
+// AddReviewer назначает ревьюера на открытый PR и записывает изменение в журнал
+func (r PullRequestRepository) AddReviewer(ctx context.Context, prID, userID, actorID string, expectedVersion int) error {
+	return r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		if err := lockOpenPR(ctx, tx, prID, expectedVersion); err != nil {
+			return err
+		}
+
+		if err := reserveReviewCapacity(ctx, tx, []string{userID}); err != nil {
+			return err
+		}
+
+		insertQuery := `
+			INSERT INTO pr_reviewers(pull_request_id, user_id, assigned_at)
+			VALUES ($1, $2, now())
+		`
+		if _, err := tx.Exec(ctx, insertQuery, prID, userID); err != nil {
+			if isUniqueViolation(err) {
+				return repository.ErrAlreadyExists
+			}
+			r.logger.Error("failed to insert reviewer",
+				zap.String("pr_id", prID),
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+			return fmt.Errorf("insert reviewer: %w", err)
+		}
+
+		if err := logReviewerChange(ctx, tx, prID, userID, domain.ReviewerChangeAdded, actorID); err != nil {
+			return err
+		}
+
+		return bumpPRVersion(ctx, tx, prID)
+	})
+}
+
+// RemoveReviewer снимает ревьюера с открытого PR без замены и записывает изменение в журнал
+func (r PullRequestRepository) RemoveReviewer(ctx context.Context, prID, userID, actorID string, expectedVersion int) error {
+	return r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		if err := lockOpenPR(ctx, tx, prID, expectedVersion); err != nil {
+			return err
+		}
+
+		deleteQuery := `
+			DELETE FROM pr_reviewers
+			WHERE pull_request_id = $1 AND user_id = $2
+		`
+		result, err := tx.Exec(ctx, deleteQuery, prID, userID)
+		if err != nil {
+			return fmt.Errorf("delete reviewer: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("reviewer not assigned: %w", repository.ErrNotFound)
+		}
+
+		if err := logReviewerChange(ctx, tx, prID, userID, domain.ReviewerChangeRemoved, actorID); err != nil {
+			return err
+		}
+
+		return bumpPRVersion(ctx, tx, prID)
+	})
+}
+
+// MarkReviewed отмечает, что ревьюер отправил ревью. Повторная отметка не меняет время первого ревью
+func (r PullRequestRepository) MarkReviewed(ctx context.Context, prID, userID string) error {
+	query := `
+		UPDATE pr_reviewers
+		SET reviewed_at = COALESCE(reviewed_at, NOW())
+		WHERE pull_request_id = $1 AND user_id = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query, prID, userID)
+	if err != nil {
+		r.logger.Error("failed to mark review submitted",
+			zap.String("pr_id", prID),
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return fmt.Errorf("mark reviewed: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListOverdueReviews возвращает просроченные назначения ревьюеров.
+// В запросе отбираются назначения старше календарного срока SLA; рабочие часы
+// (без выходных) учитываются уже в Go, так как срок в рабочих часах не меньше календарного
+func (r PullRequestRepository) ListOverdueReviews(ctx context.Context, now time.Time) ([]*domain.OverdueReview, error) {
+	query := `
+		SELECT r.pull_request_id, r.user_id, t.name, r.assigned_at, t.review_sla_hours, t.sla_action
+		FROM pr_reviewers r
+		INNER JOIN pull_requests pr ON pr.id = r.pull_request_id
+		INNER JOIN teams t ON t.id = pr.team_id
+		WHERE pr.status_id = (SELECT id FROM pr_statuses WHERE name = 'OPEN')
+		  AND t.review_sla_hours IS NOT NULL
+		  AND r.reviewed_at IS NULL
+		  AND r.escalated_at IS NULL
+		  AND r.assigned_at <= $1::timestamptz - make_interval(hours => t.review_sla_hours)
+		ORDER BY r.assigned_at, r.pull_request_id, r.user_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, now)
+	if err != nil {
+		r.logger.Error("failed to list overdue reviews", zap.Error(err))
+		return nil, fmt.Errorf("list overdue reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var overdue []*domain.OverdueReview
+	for rows.Next() {
+		var review domain.OverdueReview
+		if err := rows.Scan(
+			&review.PullRequestID,
+			&review.ReviewerID,
+			&review.TeamName,
+			&review.AssignedAt,
+			&review.SLA.Hours,
+			&review.SLA.Action,
+		); err != nil {
+			return nil, fmt.Errorf("scan overdue review: %w", err)
+		}
+
+		if review.SLA.Deadline(review.AssignedAt).After(now) {
+			continue
+		}
+		overdue = append(overdue, &review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate overdue reviews: %w", err)
+	}
+
+	return overdue, nil
+}
+
+// RecordEscalation сохраняет событие эскалации. Если просрочивший ревьюер остался на PR,
+// назначение отмечается как эскалированное, чтобы не эскалировать его повторно
+func (r PullRequestRepository) RecordEscalation(ctx context.Context, escalation *domain.ReviewEscalation) error {
+	return r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		var newReviewerID *string
+		if escalation.NewReviewerID != "" {
+			newReviewerID = &escalation.NewReviewerID
+		}
+
+		insertQuery := `
+			INSERT INTO review_escalations (pull_request_id, reviewer_id, action, new_reviewer_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`
+		err := tx.QueryRow(ctx, insertQuery,
+			escalation.PullRequestID,
+			escalation.ReviewerID,
+			escalation.Action,
+			newReviewerID,
+		).Scan(&escalation.ID, &escalation.CreatedAt)
+		if err != nil {
+			r.logger.Error("failed to record escalation",
+				zap.String("pr_id", escalation.PullRequestID),
+				zap.String("reviewer_id", escalation.ReviewerID),
+				zap.Error(err),
+			)
+			return fmt.Errorf("record escalation: %w", err)
+		}
+
+		markQuery := `
+			UPDATE pr_reviewers
+			SET escalated_at = NOW()
+			WHERE pull_request_id = $1 AND user_id = $2
+		`
+		if _, err := tx.Exec(ctx, markQuery, escalation.PullRequestID, escalation.ReviewerID); err != nil {
+			return fmt.Errorf("mark review escalated: %w", err)
+		}
+
 		return nil
 	})
 }
 
+// ListEscalations возвращает события эскалации PR
+func (r PullRequestRepository) ListEscalations(ctx context.Context, prID string) ([]*domain.ReviewEscalation, error) {
+	query := `
+		SELECT id, pull_request_id, reviewer_id, action, COALESCE(new_reviewer_id, ''), created_at
+		FROM review_escalations
+		WHERE pull_request_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, prID)
+	if err != nil {
+		r.logger.Error("failed to list escalations",
+			zap.String("pr_id", prID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list escalations: %w", err)
+	}
+	defer rows.Close()
+
+	escalations := []*domain.ReviewEscalation{}
+	for rows.Next() {
+		var e domain.ReviewEscalation
+		if err := rows.Scan(&e.ID, &e.PullRequestID, &e.ReviewerID, &e.Action, &e.NewReviewerID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan escalation: %w", err)
+		}
+		escalations = append(escalations, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate escalations: %w", err)
+	}
+
+	return escalations, nil
+}
+
+// lockOpenPR проверяет, что PR в статусе OPEN и его версия совпадает с expectedVersion (если != 0),
+// и блокирует PR до конца транзакции
+func lockOpenPR(ctx context.Context, tx pgx.Tx, prID string, expectedVersion int) error {
+	statusQuery := `
+		SELECT ps.name, pr.version
+		FROM pull_requests pr
+		INNER JOIN pr_statuses ps ON pr.status_id = ps.id
+		WHERE pr.id = $1
+		FOR UPDATE OF pr
+	`
+
+	var (
+		status  string
+		version int
+	)
+	err := tx.QueryRow(ctx, statusQuery, prID).Scan(&status, &version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("check PR status: %w", err)
+	}
+
+	if status != domain.StatusOpen {
+		return fmt.Errorf("PR is %s: %w", status, repository.ErrConflict)
+	}
+
+	if expectedVersion != 0 && version != expectedVersion {
+		return repository.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// reserveReviewCapacity блокирует до конца транзакции лимиты открытых ревью пользователей и проверяет,
+// что каждый может взять ещё одно ревью. Без блокировки параллельные назначения, прочитавшие одно
+// и то же число ревью, превысили бы лимит. Блокировки берутся в порядке ID, чтобы транзакции не ждали друг друга по кругу
+func reserveReviewCapacity(ctx context.Context, tx pgx.Tx, userIDs []string) error {
+	ids := slices.Clone(userIDs)
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, reviewCapacityLockClassID, id); err != nil {
+			return fmt.Errorf("lock review capacity %s: %w", id, err)
+		}
+	}
+
+	query := `
+		SELECT u.id
+		FROM users u
+		WHERE u.id = ANY($1)
+		  AND u.max_open_reviews IS NOT NULL
+		  AND u.max_open_reviews <= (
+		      SELECT COUNT(*)
+		      FROM pr_reviewers r
+		      INNER JOIN pull_requests pr ON pr.id = r.pull_request_id
+		      WHERE r.user_id = u.id
+		        AND pr.status_id = (SELECT id FROM pr_statuses WHERE name = 'OPEN')
+		  )
+		ORDER BY u.id
+	`
+
+	saturated, err := queryStrings(ctx, tx, query, ids)
+	if err != nil {
+		return fmt.Errorf("check review capacity: %w", err)
+	}
+	if len(saturated) > 0 {
+		return fmt.Errorf("%w: %s", repository.ErrAtCapacity, strings.Join(saturated, ", "))
+	}
+
+	return nil
+}
+
+// bumpPRVersion увеличивает версию PR после изменения ревьюеров
+func bumpPRVersion(ctx context.Context, tx pgx.Tx, prID string) error {
+	versionQuery := `UPDATE pull_requests SET version = version + 1 WHERE id = $1`
+	if _, err := tx.Exec(ctx, versionQuery, prID); err != nil {
+		return fmt.Errorf("bump PR version: %w", err)
+	}
+	return nil
+}
+
+// logReviewerChange записывает ручное изменение ревьюеров PR в журнал
+func logReviewerChange(ctx context.Context, tx pgx.Tx, prID, userID, action, actorID string) error {
+	query := `
+		INSERT INTO pr_reviewer_changes (pull_request_id, user_id, action, actor_id)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.Exec(ctx, query, prID, userID, action, actorID); err != nil {
+		return fmt.Errorf("log reviewer change: %w", err)
+	}
+	return nil
+}
+
 // GetByReviewerID возвращает все PR, где пользователь назначен ревьюером
 func (r PullRequestRepository) GetByReviewerID(ctx context.Context, reviewerID string) ([]*domain.PullRequest, error) {
 	query := `
@@ -231,7 +569,8 @@ func (r PullRequestRepository) GetByReviewerID(ctx context.Context, reviewerID s
 		    pr.id,
 		    pr.name,
 		    pr.author_id,
-		    ps.name as status
+		    ps.name as status,
+		    pr.version
 		FROM pull_requests pr
 		INNER JOIN pr_statuses ps ON pr.status_id = ps.id
 		INNER JOIN pr_reviewers rev ON rev.pull_request_id = pr.id
@@ -257,6 +596,7 @@ func (r PullRequestRepository) GetByReviewerID(ctx context.Context, reviewerID s
 			&pr.Name,
 			&pr.AuthorID,
 			&pr.Status,
+			&pr.Version,
 		)
 		if err != nil {
 			r.logger.Error("failed to scan PR row", zap.Error(err))
@@ -271,3 +611,43 @@ func (r PullRequestRepository) GetByReviewerID(ctx context.Context, reviewerID s
 
 	return prs, nil
 }
+
+// CountRecentReviews возвращает число недавних PR автора по каждому ревьюеру
+func (r PullRequestRepository) CountRecentReviews(ctx context.Context, authorID string, since time.Time) (map[string]int, error) {
+	query := `
+		SELECT rev.user_id, COUNT(*)
+		FROM pull_requests pr
+		INNER JOIN pr_reviewers rev ON rev.pull_request_id = pr.id
+		WHERE pr.author_id = $1 AND pr.created_at >= $2
+		GROUP BY rev.user_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, authorID, since)
+	if err != nil {
+		r.logger.Error("failed to count recent reviews",
+			zap.String("author_id", authorID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("count recent reviews: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			userID string
+			count  int
+		)
+		if err := rows.Scan(&userID, &count); err != nil {
+			r.logger.Error("failed to scan recent review row", zap.Error(err))
+			return nil, fmt.Errorf("scan recent review: %w", err)
+		}
+		counts[userID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recent reviews: %w", err)
+	}
+
+	return counts, nil
+}
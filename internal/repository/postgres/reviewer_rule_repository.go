@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+)
+
+type ReviewerRuleRepository struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewReviewerRuleRepository(pool *pgxpool.Pool, logger *zap.Logger) *ReviewerRuleRepository {
+	return &ReviewerRuleRepository{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+// Upsert создаёт правило для пары автор-ревьюер или меняет его тип
+func (r *ReviewerRuleRepository) Upsert(ctx context.Context, rule *domain.ReviewerRule) error {
+	query := `
+		INSERT INTO team_reviewer_rules (team_id, author_id, reviewer_id, kind, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (team_id, author_id, reviewer_id) DO UPDATE SET
+		    kind = EXCLUDED.kind,
+		    created_at = EXCLUDED.created_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		rule.TeamID,
+		rule.AuthorID,
+		rule.ReviewerID,
+		rule.Kind,
+		rule.CreatedAt,
+	)
+
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return repository.ErrNotFound
+		}
+		r.logger.Error("failed to save reviewer rule",
+			zap.Int("team_id", rule.TeamID),
+			zap.String("author_id", rule.AuthorID),
+			zap.String("reviewer_id", rule.ReviewerID),
+			zap.Error(err),
+		)
+		return fmt.Errorf("save reviewer rule: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет правило для пары автор-ревьюер
+func (r *ReviewerRuleRepository) Delete(ctx context.Context, teamID int, authorID, reviewerID string) (*domain.ReviewerRule, error) {
+	query := `
+		DELETE FROM team_reviewer_rules rr
+		USING teams t
+		WHERE t.id = rr.team_id
+		  AND rr.team_id = $1 AND rr.author_id = $2 AND rr.reviewer_id = $3
+		RETURNING rr.team_id, t.name, rr.author_id, rr.reviewer_id, rr.kind, rr.created_at
+	`
+
+	var rule domain.ReviewerRule
+	err := r.pool.QueryRow(ctx, query, teamID, authorID, reviewerID).Scan(
+		&rule.TeamID,
+		&rule.TeamName,
+		&rule.AuthorID,
+		&rule.ReviewerID,
+		&rule.Kind,
+		&rule.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		r.logger.Error("failed to delete reviewer rule",
+			zap.Int("team_id", teamID),
+			zap.String("author_id", authorID),
+			zap.String("reviewer_id", reviewerID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("delete reviewer rule: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// ListByTeamID возвращает все правила команды
+func (r *ReviewerRuleRepository) ListByTeamID(ctx context.Context, teamID int) ([]*domain.ReviewerRule, error) {
+	query := `
+		SELECT rr.team_id, t.name, rr.author_id, rr.reviewer_id, rr.kind, rr.created_at
+		FROM team_reviewer_rules rr
+		INNER JOIN teams t ON t.id = rr.team_id
+		WHERE rr.team_id = $1
+		ORDER BY rr.author_id, rr.reviewer_id
+	`
+
+	rules, err := r.query(ctx, query, teamID)
+	if err != nil {
+		r.logger.Error("failed to list team reviewer rules",
+			zap.Int("team_id", teamID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list team reviewer rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// ListByAuthor возвращает правила команды для автора PR
+func (r *ReviewerRuleRepository) ListByAuthor(ctx context.Context, teamID int, authorID string) ([]*domain.ReviewerRule, error) {
+	query := `
+		SELECT rr.team_id, t.name, rr.author_id, rr.reviewer_id, rr.kind, rr.created_at
+		FROM team_reviewer_rules rr
+		INNER JOIN teams t ON t.id = rr.team_id
+		WHERE rr.team_id = $1 AND rr.author_id = $2
+		ORDER BY rr.reviewer_id
+	`
+
+	rules, err := r.query(ctx, query, teamID, authorID)
+	if err != nil {
+		r.logger.Error("failed to list author reviewer rules",
+			zap.Int("team_id", teamID),
+			zap.String("author_id", authorID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("list author reviewer rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *ReviewerRuleRepository) query(ctx context.Context, query string, args ...any) ([]*domain.ReviewerRule, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []*domain.ReviewerRule{}
+	for rows.Next() {
+		var rule domain.ReviewerRule
+		if err := rows.Scan(
+			&rule.TeamID,
+			&rule.TeamName,
+			&rule.AuthorID,
+			&rule.ReviewerID,
+			&rule.Kind,
+			&rule.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan reviewer rule: %w", err)
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reviewer rules: %w", err)
+	}
+
+	return rules, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -16,48 +17,520 @@ import (
 )
 
 type TeamRepository struct {
-	pool   *pgxpool.Pool
-	logger *zap.Logger
+	pool      *pgxpool.Pool
+	txManager *TxManager
+	logger    *zap.Logger
 }
 
-func NewTeamRepository(pool *pgxpool.Pool, logger *zap.Logger) *TeamRepository {
+func NewTeamRepository(pool *pgxpool.Pool, txManager *TxManager, logger *zap.Logger) *TeamRepository {
 	return &TeamRepository{
-		pool:   pool,
-		logger: logger,
+		pool:      pool,
+		txManager: txManager,
+		logger:    logger,
 	}
 }
 
-// Create создаёт новую команду
-func (r *TeamRepository) Create(ctx context.Context, team *domain.Team) error {
-	query := `
-		INSERT INTO teams (name, created_at)
-		VALUES ($1, $2)
-		RETURNING id
+// CreateWithMembers создаёт команду и её участников в одной транзакции
+func (r *TeamRepository) CreateWithMembers(ctx context.Context, team *domain.Team, policy domain.TransferPolicy) (*domain.TeamTransferReport, error) {
+	report := newTransferReport()
+
+	err := r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		parentID, err := resolveParent(ctx, tx, team.ParentName)
+		if err != nil {
+			return err
+		}
+		team.ParentID = parentID
+
+		teamQuery := `
+			INSERT INTO teams (name, parent_id, min_senior_reviewers, created_at)
+			VALUES ($1, NULLIF($2, 0), $3, $4)
+			RETURNING id
+		`
+
+		err = tx.QueryRow(ctx, teamQuery, team.Name, team.ParentID, team.MinSeniorReviewers, team.CreatedAt).Scan(&team.ID)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return repository.ErrAlreadyExists
+			}
+			return fmt.Errorf("create team: %w", err)
+		}
+
+		members, err := addMembers(ctx, tx, team.ID, team.Name, team.Members, policy, report)
+		if err != nil {
+			return err
+		}
+
+		team.Members = members
+		return nil
+	})
+
+	if err != nil {
+		r.logChangeError("failed to create team with members", team.Name, err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Update переименовывает команду, добавляет и удаляет участников в одной транзакции
+func (r *TeamRepository) Update(ctx context.Context, update *domain.TeamUpdate) (*domain.TeamChangeReport, error) {
+	report := &domain.TeamChangeReport{
+		TeamTransferReport: *newTransferReport(),
+		Removed:            []string{},
+		ReleasedReviews:    []domain.ReviewAssignment{},
+	}
+
+	err := r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		teamID, err := lockTeam(ctx, tx, update.Name)
+		if err != nil {
+			return err
+		}
+
+		teamName := update.Name
+		if update.NewName != "" && update.NewName != update.Name {
+			renameQuery := `UPDATE teams SET name = $2 WHERE id = $1`
+			if _, err := tx.Exec(ctx, renameQuery, teamID, update.NewName); err != nil {
+				if isUniqueViolation(err) {
+					return repository.ErrAlreadyExists
+				}
+				return fmt.Errorf("rename team: %w", err)
+			}
+			teamName = update.NewName
+		}
+
+		if update.ParentName != nil {
+			if err := setParent(ctx, tx, teamID, teamName, *update.ParentName); err != nil {
+				return err
+			}
+		}
+
+		if update.MinSeniorReviewers != nil {
+			ruleQuery := `UPDATE teams SET min_senior_reviewers = $2 WHERE id = $1`
+			if _, err := tx.Exec(ctx, ruleQuery, teamID, *update.MinSeniorReviewers); err != nil {
+				return fmt.Errorf("update senior reviewers rule: %w", err)
+			}
+		}
+
+		if update.ChatWebhookURL != nil {
+			webhookQuery := `UPDATE teams SET chat_webhook_url = NULLIF($2, '') WHERE id = $1`
+			if _, err := tx.Exec(ctx, webhookQuery, teamID, *update.ChatWebhookURL); err != nil {
+				return fmt.Errorf("update chat webhook: %w", err)
+			}
+		}
+
+		if len(update.RemoveMembers) > 0 {
+			removeQuery := `
+				DELETE FROM team_memberships
+				WHERE team_id = $1 AND user_id = ANY($2)
+				RETURNING user_id
+			`
+
+			removed, err := queryStrings(ctx, tx, removeQuery, teamID, update.RemoveMembers)
+			if err != nil {
+				return fmt.Errorf("remove members: %w", err)
+			}
+			report.Removed = removed
+
+			report.ReleasedReviews, err = releaseOpenReviews(ctx, tx, teamID, removed)
+			if err != nil {
+				return err
+			}
+		}
+
+		transfers := &report.TeamTransferReport
+		if _, err := addMembers(ctx, tx, teamID, teamName, update.AddMembers, update.TransferPolicy, transfers); err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		r.logChangeError("failed to update team", update.Name, err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Delete удаляет команду. Участники исключаются из неё и снимаются с ревью открытых PR этой команды
+func (r *TeamRepository) Delete(ctx context.Context, name string) (*domain.TeamChangeReport, error) {
+	report := &domain.TeamChangeReport{
+		TeamTransferReport: *newTransferReport(),
+		Removed:            []string{},
+		ReleasedReviews:    []domain.ReviewAssignment{},
+	}
+
+	err := r.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		teamID, err := lockTeam(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		detachQuery := `
+			DELETE FROM team_memberships
+			WHERE team_id = $1
+			RETURNING user_id
+		`
+
+		removed, err := queryStrings(ctx, tx, detachQuery, teamID)
+		if err != nil {
+			return fmt.Errorf("detach members: %w", err)
+		}
+		report.Removed = removed
+
+		// Снимаем ревьюеров до удаления команды, пока PR ещё ссылаются на неё
+		report.ReleasedReviews, err = releaseOpenReviews(ctx, tx, teamID, removed)
+		if err != nil {
+			return err
+		}
+
+		reparentQuery := `
+			UPDATE teams
+			SET parent_id = (SELECT parent_id FROM teams WHERE id = $1)
+			WHERE parent_id = $1
+			RETURNING name
+		`
+
+		report.ReparentedSubteams, err = queryStrings(ctx, tx, reparentQuery, teamID)
+		if err != nil {
+			return fmt.Errorf("reparent subteams: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM teams WHERE id = $1`, teamID); err != nil {
+			return fmt.Errorf("delete team: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		r.logChangeError("failed to delete team", name, err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// logChangeError логирует неожиданные ошибки изменения команды
+func (r *TeamRepository) logChangeError(msg, teamName string, err error) {
+	if errors.Is(err, repository.ErrNotFound) ||
+		errors.Is(err, repository.ErrAlreadyExists) ||
+		errors.Is(err, repository.ErrConflict) ||
+		errors.Is(err, repository.ErrUsernameTaken) ||
+		errors.Is(err, repository.ErrParentNotFound) ||
+		errors.Is(err, repository.ErrHierarchyCycle) {
+		return
+	}
+
+	r.logger.Error(msg,
+		zap.String("team_name", teamName),
+		zap.Error(err),
+	)
+}
+
+func newTransferReport() *domain.TeamTransferReport {
+	return &domain.TeamTransferReport{
+		Moved:   []domain.MemberTransfer{},
+		Skipped: []domain.MemberTransfer{},
+		Joined:  []domain.MemberTransfer{},
+	}
+}
+
+// lockTeam блокирует команду до конца транзакции и возвращает её ID
+func lockTeam(ctx context.Context, tx pgx.Tx, name string) (int, error) {
+	var teamID int
+	err := tx.QueryRow(ctx, `SELECT id FROM teams WHERE name = $1 FOR UPDATE`, name).Scan(&teamID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrNotFound
+		}
+		return 0, fmt.Errorf("lock team: %w", err)
+	}
+	return teamID, nil
+}
+
+// resolveParent возвращает ID родительской команды, 0 - если parentName пустой
+func resolveParent(ctx context.Context, tx pgx.Tx, parentName string) (int, error) {
+	if parentName == "" {
+		return 0, nil
+	}
+
+	var parentID int
+	err := tx.QueryRow(ctx, `SELECT id FROM teams WHERE name = $1`, parentName).Scan(&parentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %s", repository.ErrParentNotFound, parentName)
+		}
+		return 0, fmt.Errorf("get parent team: %w", err)
+	}
+	return parentID, nil
+}
+
+// setParent переносит команду под parentName (пустая строка - сделать корневой).
+// Команду нельзя перенести под саму себя или свою подкоманду
+func setParent(ctx context.Context, tx pgx.Tx, teamID int, teamName, parentName string) error {
+	parentID, err := resolveParent(ctx, tx, parentName)
+	if err != nil {
+		return err
+	}
+
+	if parentID != 0 {
+		cycleQuery := `
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM teams WHERE id = $1
+				UNION
+				SELECT t.id FROM teams t INNER JOIN subtree s ON t.parent_id = s.id
+			)
+			SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)
+		`
+
+		var cycle bool
+		if err := tx.QueryRow(ctx, cycleQuery, teamID, parentID).Scan(&cycle); err != nil {
+			return fmt.Errorf("check team hierarchy: %w", err)
+		}
+		if cycle {
+			return fmt.Errorf("team %s cannot be nested under %s: %w", teamName, parentName, repository.ErrHierarchyCycle)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE teams SET parent_id = NULLIF($2, 0) WHERE id = $1`, teamID, parentID); err != nil {
+		return fmt.Errorf("set parent team: %w", err)
+	}
+	return nil
+}
+
+// addMembers создаёт или обновляет пользователей и добавляет их в команду.
+// Участники других команд обрабатываются согласно policy, результат записывается в report
+func addMembers(
+	ctx context.Context,
+	tx pgx.Tx,
+	teamID int,
+	teamName string,
+	users []*domain.User,
+	policy domain.TransferPolicy,
+	report *domain.TeamTransferReport,
+) ([]*domain.User, error) {
+	if len(users) == 0 {
+		return []*domain.User{}, nil
+	}
+
+	userIDs := make([]string, len(users))
+	for i, u := range users {
+		userIDs[i] = u.ID
+	}
+
+	// Блокируем существующих пользователей, чтобы их членство не изменили параллельно
+	lockQuery := `SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	if _, err := queryStrings(ctx, tx, lockQuery, userIDs); err != nil {
+		return nil, fmt.Errorf("lock existing members: %w", err)
+	}
+
+	existingQuery := `
+		SELECT m.user_id, t.id, t.name
+		FROM team_memberships m
+		INNER JOIN teams t ON t.id = m.team_id
+		WHERE m.user_id = ANY($1) AND m.team_id <> $2
+		ORDER BY m.user_id, m.joined_at, t.id
 	`
 
-	err := r.pool.QueryRow(ctx, query, team.Name, time.Now()).Scan(&team.ID)
+	rows, err := tx.Query(ctx, existingQuery, userIDs, teamID)
 	if err != nil {
-		if isUniqueViolation(err) {
-			return repository.ErrAlreadyExists
+		return nil, fmt.Errorf("get existing memberships: %w", err)
+	}
+
+	otherTeams := make(map[string][]string)
+	otherTeamIDs := make(map[string][]int)
+	for rows.Next() {
+		var (
+			userID, otherTeam string
+			otherTeamID       int
+		)
+		if err := rows.Scan(&userID, &otherTeamID, &otherTeam); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan existing membership: %w", err)
 		}
-		r.logger.Error("failed to create team",
-			zap.String("team_name", team.Name),
-			zap.Error(err),
+		otherTeams[userID] = append(otherTeams[userID], otherTeam)
+		otherTeamIDs[userID] = append(otherTeamIDs[userID], otherTeamID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate existing memberships: %w", err)
+	}
+
+	if policy == domain.TransferPolicyReject && len(otherTeams) > 0 {
+		conflicting := make([]string, 0, len(otherTeams))
+		for _, id := range userIDs {
+			if teams, ok := otherTeams[id]; ok {
+				conflicting = append(conflicting, fmt.Sprintf("%s (%s)", id, strings.Join(teams, ", ")))
+			}
+		}
+		return nil, fmt.Errorf("users already belong to other teams: %s: %w",
+			strings.Join(conflicting, ", "), repository.ErrConflict)
+	}
+
+	userQuery := `
+		INSERT INTO users (id, username, is_active, created_at, updated_at, email)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT (id) DO UPDATE SET
+		   username = EXCLUDED.username,
+		   is_active = EXCLUDED.is_active,
+		   email = COALESCE(EXCLUDED.email, users.email),
+		   updated_at = EXCLUDED.updated_at
+	`
+
+	leaveQuery := `DELETE FROM team_memberships WHERE user_id = $1 AND team_id <> $2`
+
+	membershipQuery := `
+		INSERT INTO team_memberships (team_id, user_id, joined_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_id, user_id) DO NOTHING
+	`
+
+	members := make([]*domain.User, 0, len(users))
+	for _, u := range users {
+		teams := otherTeams[u.ID]
+		transfers := make([]domain.MemberTransfer, len(teams))
+		for i, fromTeam := range teams {
+			transfers[i] = domain.MemberTransfer{UserID: u.ID, FromTeam: fromTeam}
+		}
+
+		switch {
+		case len(teams) == 0:
+		case policy == domain.TransferPolicySkip:
+			report.Skipped = append(report.Skipped, transfers...)
+			continue
+		case policy == domain.TransferPolicyMove:
+			if _, err := tx.Exec(ctx, leaveQuery, u.ID, teamID); err != nil {
+				return nil, fmt.Errorf("leave other teams %s: %w", u.ID, err)
+			}
+			// Покинув команду, участник больше не ревьюит её открытые PR
+			for i, fromTeamID := range otherTeamIDs[u.ID] {
+				released, err := releaseOpenReviews(ctx, tx, fromTeamID, []string{u.ID})
+				if err != nil {
+					return nil, err
+				}
+				for _, a := range released {
+					transfers[i].ReleasedReviews = append(transfers[i].ReleasedReviews, a.PullRequestID)
+				}
+			}
+			report.Moved = append(report.Moved, transfers...)
+			teams = nil
+		case policy == domain.TransferPolicyJoin:
+			report.Joined = append(report.Joined, transfers...)
+		}
+
+		u.TeamID = teamID
+		u.TeamName = teamName
+		u.Teams = append(append([]string{}, teams...), teamName)
+
+		_, err := tx.Exec(ctx, userQuery,
+			u.ID,
+			u.Username,
+			u.IsActive,
+			u.CreatedAt,
+			u.UpdatedAt,
+			u.Email,
 		)
-		return fmt.Errorf("create team: %w", err)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return nil, fmt.Errorf("%w: %s", repository.ErrUsernameTaken, u.Username)
+			}
+			return nil, fmt.Errorf("create/update user %s: %w", u.ID, err)
+		}
+
+		if _, err := tx.Exec(ctx, membershipQuery, teamID, u.ID, u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("add membership %s: %w", u.ID, err)
+		}
+
+		members = append(members, u)
 	}
 
-	return nil
+	return members, nil
+}
+
+// releaseOpenReviews снимает пользователей с ревью открытых PR команды и увеличивает версии этих PR
+func releaseOpenReviews(ctx context.Context, tx pgx.Tx, teamID int, userIDs []string) ([]domain.ReviewAssignment, error) {
+	released := []domain.ReviewAssignment{}
+	if len(userIDs) == 0 {
+		return released, nil
+	}
+
+	releaseQuery := `
+		DELETE FROM pr_reviewers rev
+		USING pull_requests pr
+		WHERE rev.pull_request_id = pr.id
+		  AND rev.user_id = ANY($1)
+		  AND pr.team_id = $2
+		  AND pr.status_id = (SELECT id FROM pr_statuses WHERE name = 'OPEN')
+		RETURNING rev.pull_request_id, rev.user_id
+	`
+
+	rows, err := tx.Query(ctx, releaseQuery, userIDs, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("release open reviews: %w", err)
+	}
+
+	prIDs := make([]string, 0)
+	for rows.Next() {
+		var a domain.ReviewAssignment
+		if err := rows.Scan(&a.PullRequestID, &a.UserID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan released review: %w", err)
+		}
+		released = append(released, a)
+		prIDs = append(prIDs, a.PullRequestID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate released reviews: %w", err)
+	}
+
+	if len(prIDs) > 0 {
+		versionQuery := `UPDATE pull_requests SET version = version + 1 WHERE id = ANY($1)`
+		if _, err := tx.Exec(ctx, versionQuery, prIDs); err != nil {
+			return nil, fmt.Errorf("bump PR versions: %w", err)
+		}
+	}
+
+	return released, nil
+}
+
+// queryStrings выполняет запрос, возвращающий одну строковую колонку
+func queryStrings(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	if values == nil {
+		values = []string{}
+	}
+
+	return values, nil
 }
 
 // GetByName возвращает команду по имени с участниками
 func (r *TeamRepository) GetByName(ctx context.Context, name string) (*domain.Team, error) {
 	query := `
 		SELECT 
-		    t.id, t.name, t.created_at,
-		    u.id, u.username, u.team_id, u.is_active, u.created_at, u.updated_at
+		    t.id, t.name, COALESCE(t.parent_id, 0), t.min_senior_reviewers,
+		    t.review_sla_hours, t.sla_action, COALESCE(t.chat_webhook_url, ''), t.created_at,
+		    u.id, u.username, u.is_active, u.seniority, u.created_at, u.updated_at,
+		    (
+		        SELECT array_agg(ut.name ORDER BY um.joined_at, ut.id)
+		        FROM team_memberships um
+		        INNER JOIN teams ut ON ut.id = um.team_id
+		        WHERE um.user_id = u.id
+		    ) as user_teams
 		FROM teams t
-		LEFT JOIN users u ON u.team_id = t.id
+		LEFT JOIN team_memberships m ON m.team_id = t.id
+		LEFT JOIN users u ON u.id = m.user_id
 		WHERE t.name = $1
 		ORDER BY u.id
  	`
@@ -78,20 +551,25 @@ func (r *TeamRepository) GetByName(ctx context.Context, name string) (*domain.Te
 
 		teamID        int
 		teamName      string
+		teamParentID  int
+		teamSeniors   int
+		teamSLA       slaColumns
+		teamWebhook   string
 		teamCreatedAt time.Time
 
 		userID        *string
 		username      *string
-		userTeamID    *int
 		isActive      *bool
+		seniority     *string
 		userCreatedAt *time.Time
 		userUpdatedAt *time.Time
+		userTeams     []string
 	)
 
 	for rows.Next() {
 		err := rows.Scan(
-			&teamID, &teamName, &teamCreatedAt,
-			&userID, &username, &userTeamID, &isActive, &userCreatedAt, &userUpdatedAt,
+			&teamID, &teamName, &teamParentID, &teamSeniors, &teamSLA.hours, &teamSLA.action, &teamWebhook, &teamCreatedAt,
+			&userID, &username, &isActive, &seniority, &userCreatedAt, &userUpdatedAt, &userTeams,
 		)
 		if err != nil {
 			r.logger.Error("failed to scan team row", zap.Error(err))
@@ -100,18 +578,26 @@ func (r *TeamRepository) GetByName(ctx context.Context, name string) (*domain.Te
 
 		if team == nil {
 			team = &domain.Team{
-				ID:        teamID,
-				Name:      teamName,
-				CreatedAt: teamCreatedAt,
+				ID:                 teamID,
+				Name:               teamName,
+				ParentID:           teamParentID,
+				MinSeniorReviewers: teamSeniors,
+				ReviewSLA:          teamSLA.toDomain(),
+				CreatedAt:          teamCreatedAt,
+
+				ChatWebhookURL:        teamWebhook,
+				ChatWebhookConfigured: teamWebhook != "",
 			}
 		}
 		if userID != nil {
 			members = append(members, &domain.User{
 				ID:        *userID,
 				Username:  *username,
-				TeamID:    *userTeamID,
+				TeamID:    teamID,
 				TeamName:  teamName,
+				Teams:     userTeams,
 				IsActive:  *isActive,
+				Seniority: domain.Seniority(*seniority),
 				CreatedAt: *userCreatedAt,
 				UpdatedAt: *userUpdatedAt,
 			})
@@ -133,15 +619,24 @@ func (r *TeamRepository) GetByName(ctx context.Context, name string) (*domain.Te
 // GetByID возвращает команду по ID
 func (r *TeamRepository) GetByID(ctx context.Context, id int) (*domain.Team, error) {
 	query := `
-		SELECT id, name, created_at
+		SELECT id, name, COALESCE(parent_id, 0), min_senior_reviewers, review_sla_hours, sla_action,
+		       COALESCE(chat_webhook_url, ''), created_at
 		FROM teams
 		WHERE id = $1
 	`
 
-	var team domain.Team
+	var (
+		team domain.Team
+		sla  slaColumns
+	)
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&team.ID,
 		&team.Name,
+		&team.ParentID,
+		&team.MinSeniorReviewers,
+		&sla.hours,
+		&sla.action,
+		&team.ChatWebhookURL,
 		&team.CreatedAt,
 	)
 
@@ -155,10 +650,118 @@ func (r *TeamRepository) GetByID(ctx context.Context, id int) (*domain.Team, err
 		)
 		return nil, fmt.Errorf("get team: %w", err)
 	}
+	team.ReviewSLA = sla.toDomain()
+	team.ChatWebhookConfigured = team.ChatWebhookURL != ""
 
 	return &team, nil
 }
 
+// UpdateReviewSLA задаёт SLA ревью команды, nil снимает SLA
+func (r *TeamRepository) UpdateReviewSLA(ctx context.Context, teamID int, sla *domain.ReviewSLA) error {
+	var (
+		hours  *int
+		action = domain.SLAActionReassign
+	)
+	if sla != nil {
+		hours = &sla.Hours
+		action = sla.Action
+	}
+
+	query := `UPDATE teams SET review_sla_hours = $2, sla_action = $3 WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, teamID, hours, action)
+	if err != nil {
+		r.logger.Error("failed to update team review SLA",
+			zap.Int("team_id", teamID),
+			zap.Error(err),
+		)
+		return fmt.Errorf("update review SLA: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// slaColumns - колонки SLA ревью команды
+type slaColumns struct {
+	hours  *int
+	action string
+}
+
+func (c slaColumns) toDomain() *domain.ReviewSLA {
+	if c.hours == nil {
+		return nil
+	}
+	return &domain.ReviewSLA{Hours: *c.hours, Action: domain.SLAAction(c.action)}
+}
+
+// ListNodes возвращает все команды с числом участников и открытых PR, в том числе с учётом подкоманд
+func (r *TeamRepository) ListNodes(ctx context.Context) ([]*domain.TeamNode, error) {
+	query := `
+		WITH RECURSIVE subtree (root_id, team_id, path) AS (
+			SELECT id, id, ARRAY[id] FROM teams
+			UNION ALL
+			SELECT s.root_id, t.id, s.path || t.id
+			FROM subtree s
+			INNER JOIN teams t ON t.parent_id = s.team_id
+			WHERE t.id <> ALL(s.path)
+		),
+		open_prs AS (
+			SELECT team_id, COUNT(*) AS cnt
+			FROM pull_requests
+			WHERE status_id = (SELECT id FROM pr_statuses WHERE name = 'OPEN')
+			GROUP BY team_id
+		)
+		SELECT t.id, COALESCE(t.parent_id, 0), t.name,
+		       (SELECT COUNT(*) FROM team_memberships m WHERE m.team_id = t.id),
+		       (SELECT COUNT(DISTINCT m.user_id)
+		        FROM subtree s
+		        INNER JOIN team_memberships m ON m.team_id = s.team_id
+		        WHERE s.root_id = t.id),
+		       COALESCE((SELECT cnt FROM open_prs p WHERE p.team_id = t.id), 0),
+		       (SELECT COALESCE(SUM(p.cnt), 0)
+		        FROM subtree s
+		        INNER JOIN open_prs p ON p.team_id = s.team_id
+		        WHERE s.root_id = t.id)
+		FROM teams t
+		ORDER BY t.name
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to list teams", zap.Error(err))
+		return nil, fmt.Errorf("list teams: %w", err)
+	}
+	defer rows.Close()
+
+	nodes := []*domain.TeamNode{}
+	for rows.Next() {
+		var node domain.TeamNode
+		err := rows.Scan(
+			&node.ID,
+			&node.ParentID,
+			&node.Name,
+			&node.MembersCount,
+			&node.TotalMembersCount,
+			&node.OpenPRsCount,
+			&node.TotalOpenPRsCount,
+		)
+		if err != nil {
+			r.logger.Error("failed to scan team row", zap.Error(err))
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		nodes = append(nodes, &node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate teams: %w", err)
+	}
+
+	return nodes, nil
+}
+
 func isUniqueViolation(err error) bool {
 	// pgx возвращает ошибку с кодом 23505 для нарушения уникальности
 	var pgErr *pgconn.PgError
@@ -167,3 +770,12 @@ func isUniqueViolation(err error) bool {
 	}
 	return false
 }
+
+func isForeignKeyViolation(err error) bool {
+	// 23503 - нарушение внешнего ключа
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23503"
+	}
+	return false
+}
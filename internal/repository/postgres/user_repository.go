@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -25,54 +26,62 @@ func NewUserRepository(pool *pgxpool.Pool, logger *zap.Logger) *UserRepository {
 	}
 }
 
-// Create создает или обновляет пользователя
-func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
-	query := `
-		INSERT INTO users (id, username, team_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (id) DO UPDATE SET 
-		   username = EXCLUDED.username,
-		   team_id = EXCLUDED.team_id,
-		   is_active = EXCLUDED.is_active,
-		   updated_at = EXCLUDED.updated_at
-	`
-
-	_, err := r.pool.Exec(ctx, query,
-		user.ID,
-		user.Username,
-		user.TeamID,
-		user.IsActive,
-		user.CreatedAt,
-		user.UpdatedAt,
-	)
-
-	if err != nil {
-		r.logger.Error("failed to create user",
-			zap.String("user_id", user.ID),
-			zap.Error(err),
-		)
-		return fmt.Errorf("create user: %w", err)
-	}
-
-	return nil
-}
-
-// GetByID возвращает пользователя по ID
+// GetByID возвращает пользователя по ID со списком его команд.
+// Основная команда - та, в которую пользователь вступил первой
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	query := `
-		SELECT u.id, u.username, u.team_id, t.name as team_name, u.is_active, u.created_at, u.updated_at
+		SELECT
+		    u.id,
+		    u.username,
+		    COALESCE(u.email, ''),
+		    COALESCE(u.chat_handle, ''),
+		    COALESCE((array_agg(t.id ORDER BY m.joined_at, t.id) FILTER ( WHERE t.id IS NOT NULL ))[1], 0) as team_id,
+		    COALESCE(array_agg(t.name ORDER BY m.joined_at, t.id) FILTER ( WHERE t.id IS NOT NULL ), '{}') as teams,
+		    u.is_active,
+		    u.timezone,
+		    to_char(u.work_start, 'HH24:MI'),
+		    to_char(u.work_end, 'HH24:MI'),
+		    u.work_days,
+		    u.max_open_reviews,
+		    u.review_weight,
+		    u.seniority,
+		    u.digest_opt_out,
+		    u.quiet_timezone,
+		    to_char(u.quiet_start, 'HH24:MI'),
+		    to_char(u.quiet_end, 'HH24:MI'),
+		    u.created_at,
+		    u.updated_at
 		FROM users u
-		INNER JOIN teams t ON u.team_id = t.id
+		LEFT JOIN team_memberships m ON m.user_id = u.id
+		LEFT JOIN teams t ON t.id = m.team_id
 		WHERE u.id = $1
+		GROUP BY u.id
 	`
 
-	var user domain.User
+	var (
+		user     domain.User
+		schedule scheduleColumns
+		quiet    quietHoursColumns
+	)
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Username,
+		&user.Email,
+		&user.ChatHandle,
 		&user.TeamID,
-		&user.TeamName,
+		&user.Teams,
 		&user.IsActive,
+		&schedule.timezone,
+		&schedule.start,
+		&schedule.end,
+		&schedule.days,
+		&user.MaxOpenReviews,
+		&user.ReviewWeight,
+		&user.Seniority,
+		&user.DigestOptOut,
+		&quiet.timezone,
+		&quiet.start,
+		&quiet.end,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -88,9 +97,52 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 		return nil, fmt.Errorf("get user: %w", err)
 	}
 
+	if len(user.Teams) > 0 {
+		user.TeamName = user.Teams[0]
+	}
+	user.Schedule = schedule.toDomain()
+	user.QuietHours = quiet.toDomain()
+
 	return &user, nil
 }
 
+// GetTeams возвращает команды пользователя в порядке вступления
+func (r *UserRepository) GetTeams(ctx context.Context, userID string) ([]*domain.Team, error) {
+	query := `
+		SELECT t.id, t.name, t.min_senior_reviewers, t.created_at
+		FROM team_memberships m
+		INNER JOIN teams t ON t.id = m.team_id
+		WHERE m.user_id = $1
+		ORDER BY m.joined_at, t.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("failed to get user teams",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get user teams: %w", err)
+	}
+	defer rows.Close()
+
+	teams := []*domain.Team{}
+	for rows.Next() {
+		var team domain.Team
+		if err := rows.Scan(&team.ID, &team.Name, &team.MinSeniorReviewers, &team.CreatedAt); err != nil {
+			r.logger.Error("failed to scan team row", zap.Error(err))
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		teams = append(teams, &team)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate teams: %w", err)
+	}
+
+	return teams, nil
+}
+
 // UpdateIsActive изменяет статус активности пользователя
 func (r *UserRepository) UpdateIsActive(ctx context.Context, id string, isActive bool) error {
 	query := `
@@ -116,49 +168,300 @@ func (r *UserRepository) UpdateIsActive(ctx context.Context, id string, isActive
 	return nil
 }
 
-// GetActiveUsersByTeamID возвращает активных пользователей команды, исключая указанных
-func (r *UserRepository) GetActiveUsersByTeamID(ctx context.Context, teamID int, excludeUserIDs []string) ([]*domain.User, error) {
+// UpdateWorkSchedule задает рабочее время пользователя. nil сбрасывает расписание
+func (r *UserRepository) UpdateWorkSchedule(ctx context.Context, id string, schedule *domain.WorkSchedule) error {
+	query := `
+		UPDATE users
+		SET timezone = $2, work_start = $3::time, work_end = $4::time, work_days = $5, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	var tz, start, end *string
+	var days []int16
+	if schedule != nil {
+		tz, start, end = &schedule.Timezone, &schedule.Start, &schedule.End
+		days = make([]int16, len(schedule.Days))
+		for i, d := range schedule.Days {
+			days[i] = int16(d)
+		}
+	}
+
+	result, err := r.pool.Exec(ctx, query, id, tz, start, end, days)
+	if err != nil {
+		r.logger.Error("failed to update user work schedule",
+			zap.String("user_id", id),
+			zap.Error(err),
+		)
+		return fmt.Errorf("update user work schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateMaxOpenReviews задает лимит открытых ревью пользователя. nil снимает ограничение
+func (r *UserRepository) UpdateMaxOpenReviews(ctx context.Context, id string, maxOpenReviews *int) error {
+	query := `
+		UPDATE users
+		SET max_open_reviews = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, maxOpenReviews)
+	if err != nil {
+		r.logger.Error("failed to update user review capacity",
+			zap.String("user_id", id),
+			zap.Error(err),
+		)
+		return fmt.Errorf("update user review capacity: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateSettings изменяет заданные (не nil) настройки пользователя
+func (r *UserRepository) UpdateSettings(ctx context.Context, id string, settings *domain.UserSettings) error {
+	query := `
+		UPDATE users
+		SET review_weight = COALESCE($2, review_weight),
+		    seniority = COALESCE($3, seniority),
+		    digest_opt_out = COALESCE($4, digest_opt_out),
+		    email = CASE WHEN $5::text IS NULL THEN email ELSE NULLIF($5, '') END,
+		    chat_handle = CASE WHEN $6::text IS NULL THEN chat_handle ELSE NULLIF($6, '') END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, settings.ReviewWeight, settings.Seniority, settings.DigestOptOut, settings.Email, settings.ChatHandle)
+	if err != nil {
+		r.logger.Error("failed to update user settings",
+			zap.String("user_id", id),
+			zap.Error(err),
+		)
+		return fmt.Errorf("update user settings: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateQuietHours задает тихие часы пользователя. nil сбрасывает их
+func (r *UserRepository) UpdateQuietHours(ctx context.Context, id string, quietHours *domain.QuietHours) error {
 	query := `
-		SELECT u.id, u.username, u.team_id, t.name as team_name, u.is_active, u.created_at, u.updated_at
+		UPDATE users
+		SET quiet_timezone = $2, quiet_start = $3::time, quiet_end = $4::time, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	var tz, start, end *string
+	if quietHours != nil {
+		tz, start, end = &quietHours.Timezone, &quietHours.Start, &quietHours.End
+	}
+
+	result, err := r.pool.Exec(ctx, query, id, tz, start, end)
+	if err != nil {
+		r.logger.Error("failed to update user quiet hours",
+			zap.String("user_id", id),
+			zap.Error(err),
+		)
+		return fmt.Errorf("update user quiet hours: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDigestRecipients возвращает активных пользователей, не отказавшихся от сводок,
+// у которых есть открытые PR на ревью и последняя сводка отправлена раньше sentBefore (или не отправлялась)
+func (r *UserRepository) ListDigestRecipients(ctx context.Context, sentBefore time.Time) ([]*domain.User, error) {
+	query := `
+		SELECT u.id, u.username, COALESCE(u.email, ''), u.quiet_timezone, to_char(u.quiet_start, 'HH24:MI'), to_char(u.quiet_end, 'HH24:MI')
 		FROM users u
-		INNER JOIN teams t ON u.team_id = t.id
-		WHERE u.team_id = $1 
-		  AND u.is_active = TRUE
-		  AND u.id != ALL($2)
+		WHERE u.is_active
+		  AND NOT u.digest_opt_out
+		  AND (u.last_digest_at IS NULL OR u.last_digest_at < $1)
+		  AND EXISTS (
+		      SELECT 1
+		      FROM pr_reviewers rev
+		      INNER JOIN pull_requests pr ON pr.id = rev.pull_request_id
+		      INNER JOIN pr_statuses ps ON ps.id = pr.status_id
+		      WHERE rev.user_id = u.id AND ps.name = 'OPEN'
+		  )
+		ORDER BY u.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, sentBefore)
+	if err != nil {
+		r.logger.Error("failed to list digest recipients", zap.Error(err))
+		return nil, fmt.Errorf("list digest recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		var (
+			user  domain.User
+			quiet quietHoursColumns
+		)
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &quiet.timezone, &quiet.start, &quiet.end); err != nil {
+			return nil, fmt.Errorf("scan digest recipient: %w", err)
+		}
+		user.IsActive = true
+		user.QuietHours = quiet.toDomain()
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate digest recipients: %w", err)
+	}
+
+	return users, nil
+}
+
+// MarkDigestSent запоминает время отправки сводки пользователю
+func (r *UserRepository) MarkDigestSent(ctx context.Context, id string, sentAt time.Time) error {
+	query := `UPDATE users SET last_digest_at = $2 WHERE id = $1`
+
+	result, err := r.pool.Exec(ctx, query, id, sentAt)
+	if err != nil {
+		r.logger.Error("failed to mark digest sent",
+			zap.String("user_id", id),
+			zap.Error(err),
+		)
+		return fmt.Errorf("mark digest sent: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// quietHoursColumns - nullable-колонки тихих часов пользователя
+type quietHoursColumns struct {
+	timezone *string
+	start    *string
+	end      *string
+}
+
+func (c quietHoursColumns) toDomain() *domain.QuietHours {
+	if c.timezone == nil || c.start == nil || c.end == nil {
+		return nil
+	}
+	return &domain.QuietHours{Timezone: *c.timezone, Start: *c.start, End: *c.end}
+}
+
+// scheduleColumns - nullable-колонки расписания пользователя
+type scheduleColumns struct {
+	timezone *string
+	start    *string
+	end      *string
+	days     []int16
+}
+
+func (c scheduleColumns) toDomain() *domain.WorkSchedule {
+	if c.timezone == nil || c.start == nil || c.end == nil {
+		return nil
+	}
+
+	days := make([]int, len(c.days))
+	for i, d := range c.days {
+		days[i] = int(d)
+	}
+
+	return &domain.WorkSchedule{
+		Timezone: *c.timezone,
+		Start:    *c.start,
+		End:      *c.end,
+		Days:     days,
+	}
+}
+
+// GetAssignmentPool возвращает всех участников команды с числом открытых ревью и признаком отсутствия.
+// TeamID и TeamName пользователей - запрошенная команда
+func (r *UserRepository) GetAssignmentPool(ctx context.Context, teamID int) ([]*domain.PoolMember, error) {
+	query := `
+		SELECT u.id, u.username, m.team_id, t.name AS team_name, u.is_active,
+		       u.timezone, to_char(u.work_start, 'HH24:MI'), to_char(u.work_end, 'HH24:MI'), u.work_days,
+		       u.max_open_reviews, u.review_weight, u.seniority,
+		       (
+		           SELECT COUNT(*)
+		           FROM pr_reviewers r
+		           INNER JOIN pull_requests pr ON pr.id = r.pull_request_id
+		           WHERE r.user_id = u.id
+		             AND pr.status_id = (SELECT id FROM pr_statuses WHERE name = 'OPEN')
+		       ) AS open_reviews,
+		       EXISTS (
+		           SELECT 1 FROM user_absences a
+		           WHERE a.user_id = u.id AND a.starts_at <= NOW() AND a.ends_at > NOW()
+		       ) AS is_absent,
+		       u.created_at, u.updated_at
+		FROM team_memberships m
+		INNER JOIN users u ON u.id = m.user_id
+		INNER JOIN teams t ON t.id = m.team_id
+		WHERE m.team_id = $1
 		ORDER BY u.id
 	`
 
-	rows, err := r.pool.Query(ctx, query, teamID, excludeUserIDs)
+	rows, err := r.pool.Query(ctx, query, teamID)
 	if err != nil {
-		r.logger.Error("failed to get active users by team",
+		r.logger.Error("failed to get assignment pool",
 			zap.Int("team_id", teamID),
 			zap.Error(err),
 		)
-		return nil, fmt.Errorf("get active users by team: %w", err)
+		return nil, fmt.Errorf("get assignment pool: %w", err)
 	}
 	defer rows.Close()
 
-	var users []*domain.User
+	var members []*domain.PoolMember
 	for rows.Next() {
-		var user domain.User
+		var (
+			user     domain.User
+			member   = domain.PoolMember{User: &user}
+			schedule scheduleColumns
+		)
 		if err := rows.Scan(
 			&user.ID,
 			&user.Username,
 			&user.TeamID,
 			&user.TeamName,
 			&user.IsActive,
+			&schedule.timezone,
+			&schedule.start,
+			&schedule.end,
+			&schedule.days,
+			&user.MaxOpenReviews,
+			&user.ReviewWeight,
+			&user.Seniority,
+			&member.OpenReviews,
+			&member.IsAbsent,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		); err != nil {
-			r.logger.Error("failed to scan user row", zap.Error(err))
-			return nil, fmt.Errorf("scan user: %w", err)
+			r.logger.Error("failed to scan pool member row", zap.Error(err))
+			return nil, fmt.Errorf("scan pool member: %w", err)
 		}
-		users = append(users, &user)
+		user.Schedule = schedule.toDomain()
+		members = append(members, &member)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate users: %w", err)
+		return nil, fmt.Errorf("iterate pool members: %w", err)
 	}
 
-	return users, nil
+	return members, nil
 }
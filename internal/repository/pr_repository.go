@@ -8,9 +8,29 @@ import (
 )
 
 type PullRequestRepository interface {
+	// Create, ReplaceReviewer и AddReviewer возвращают ErrAtCapacity, если назначаемый ревьюер
+	// уже достиг лимита открытых ревью. Лимит проверяется в той же транзакции, что и назначение
 	Create(ctx context.Context, pr *domain.PullRequest, reviewerIDs []string) error
 	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
-	UpdateStatus(ctx context.Context, id string, status string, mergedAt *time.Time) error
-	ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) error
+	// UpdateStatus, ReplaceReviewer, AddReviewer и RemoveReviewer увеличивают версию PR.
+	// Если expectedVersion != 0 и не совпадает с текущей версией, возвращается ErrVersionMismatch.
+	// UpdateStatus возвращает changed = false, если PR уже не был открыт и статус не изменился
+	UpdateStatus(ctx context.Context, id string, status string, mergedAt *time.Time, expectedVersion int) (changed bool, err error)
+	// ReplaceReviewer, AddReviewer и RemoveReviewer записывают изменения с actorID в журнал изменений ревьюеров.
+	ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID, actorID string, expectedVersion int) error
+	// AddReviewer возвращает ErrAlreadyExists, если ревьюер уже назначен, RemoveReviewer - ErrNotFound, если не назначен
+	AddReviewer(ctx context.Context, prID, userID, actorID string, expectedVersion int) error
+	RemoveReviewer(ctx context.Context, prID, userID, actorID string, expectedVersion int) error
 	GetByReviewerID(ctx context.Context, reviewerID string) ([]*domain.PullRequest, error)
+	// MarkReviewed отмечает, что ревьюер отправил ревью. Возвращает ErrNotFound, если ревьюер не назначен
+	MarkReviewed(ctx context.Context, prID, userID string) error
+	// ListOverdueReviews возвращает назначения в открытых PR, по которым ревью не отправлено
+	// и не эскалировано, а срок SLA команды PR истёк к моменту now
+	ListOverdueReviews(ctx context.Context, now time.Time) ([]*domain.OverdueReview, error)
+	// RecordEscalation сохраняет событие эскалации и отмечает назначение как эскалированное
+	RecordEscalation(ctx context.Context, escalation *domain.ReviewEscalation) error
+	// ListEscalations возвращает события эскалации PR в порядке создания
+	ListEscalations(ctx context.Context, prID string) ([]*domain.ReviewEscalation, error)
+	// CountRecentReviews возвращает, сколько PR автора, созданных после since, ревьюит каждый пользователь
+	CountRecentReviews(ctx context.Context, authorID string, since time.Time) (map[string]int, error)
 }
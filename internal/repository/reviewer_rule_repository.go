@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
+
+// ReviewerRuleRepository хранит правила назначения ревьюеров для пар автор-ревьюер
+type ReviewerRuleRepository interface {
+	// Upsert создаёт правило или меняет тип существующего. Если пользователя нет, возвращается ErrNotFound
+	Upsert(ctx context.Context, rule *domain.ReviewerRule) error
+	// Delete удаляет правило и возвращает его. Если правила нет, возвращается ErrNotFound
+	Delete(ctx context.Context, teamID int, authorID, reviewerID string) (*domain.ReviewerRule, error)
+	ListByTeamID(ctx context.Context, teamID int) ([]*domain.ReviewerRule, error)
+	// ListByAuthor возвращает правила команды для автора PR
+	ListByAuthor(ctx context.Context, teamID int, authorID string) ([]*domain.ReviewerRule, error)
+}
@@ -2,11 +2,23 @@ package repository
 
 import (
 	"context"
+
 	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
 )
 
 type TeamRepository interface {
-	Create(ctx context.Context, team *domain.Team) error
+	// CreateWithMembers атомарно создаёт команду и её участников. Родитель задаётся через team.ParentName.
+	// Участники из других команд обрабатываются согласно policy; при TransferPolicyReject возвращается ErrConflict
+	CreateWithMembers(ctx context.Context, team *domain.Team, policy domain.TransferPolicy) (*domain.TeamTransferReport, error)
+	// Update и Delete снимают покинувших команду участников с ревью открытых PR этой команды.
+	// Update возвращает ErrHierarchyCycle, если новый родитель - сама команда или её подкоманда;
+	// Delete переносит подкоманды к родителю удаляемой команды
+	Update(ctx context.Context, update *domain.TeamUpdate) (*domain.TeamChangeReport, error)
+	Delete(ctx context.Context, name string) (*domain.TeamChangeReport, error)
 	GetByName(ctx context.Context, name string) (*domain.Team, error)
 	GetByID(ctx context.Context, id int) (*domain.Team, error)
+	// UpdateReviewSLA задаёт SLA ревью команды, nil снимает SLA
+	UpdateReviewSLA(ctx context.Context, teamID int, sla *domain.ReviewSLA) error
+	// ListNodes возвращает все команды без участников, но со счётчиками участников и открытых PR, отсортированные по имени
+	ListNodes(ctx context.Context) ([]*domain.TeamNode, error)
 }
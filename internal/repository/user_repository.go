@@ -2,13 +2,31 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
 )
 
 // UserRepository определяет методы для работы с пользователями
 type UserRepository interface {
-	Create(ctx context.Context, user *domain.User) error
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 	UpdateIsActive(ctx context.Context, id string, isActive bool) error
-	GetActiveUsersByTeamID(ctx context.Context, teamID int, excludedUserIDs []string) ([]*domain.User, error)
+	// UpdateWorkSchedule задает рабочее время пользователя, nil сбрасывает расписание
+	UpdateWorkSchedule(ctx context.Context, id string, schedule *domain.WorkSchedule) error
+	// UpdateMaxOpenReviews задает лимит открытых ревью пользователя, nil снимает ограничение
+	UpdateMaxOpenReviews(ctx context.Context, id string, maxOpenReviews *int) error
+	// UpdateSettings изменяет заданные (не nil) настройки пользователя
+	UpdateSettings(ctx context.Context, id string, settings *domain.UserSettings) error
+	// UpdateQuietHours задает тихие часы пользователя, nil сбрасывает их
+	UpdateQuietHours(ctx context.Context, id string, quietHours *domain.QuietHours) error
+	// ListDigestRecipients возвращает активных пользователей с открытыми PR на ревью, не отказавшихся от сводок,
+	// которым сводка не отправлялась с момента sentBefore. Загружаются только ID, имя и тихие часы
+	ListDigestRecipients(ctx context.Context, sentBefore time.Time) ([]*domain.User, error)
+	// MarkDigestSent запоминает время отправки сводки пользователю
+	MarkDigestSent(ctx context.Context, id string, sentAt time.Time) error
+	// GetTeams возвращает команды пользователя (без участников) в порядке вступления
+	GetTeams(ctx context.Context, userID string) ([]*domain.Team, error)
+	// GetAssignmentPool возвращает всех участников команды с данными, по которым отбираются кандидаты в ревьюеры:
+	// число открытых ревью и признак отсутствия в данный момент (см. AbsenceRepository)
+	GetAssignmentPool(ctx context.Context, teamID int) ([]*domain.PoolMember, error)
 }
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+)
+
+// DigestService рассылает пользователям сводки открытых PR, ожидающих их ревью
+type DigestService struct {
+	userRepo repository.UserRepository
+	prRepo   repository.PullRequestRepository
+	notifier notify.DigestNotifier
+	period   time.Duration
+	logger   *zap.Logger
+}
+
+// NewDigestService создаёт сервис сводок. period - минимальный интервал между сводками одному пользователю
+func NewDigestService(
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	notifier notify.DigestNotifier,
+	period time.Duration,
+	logger *zap.Logger,
+) *DigestService {
+	return &DigestService{
+		userRepo: userRepo,
+		prRepo:   prRepo,
+		notifier: notifier,
+		period:   period,
+		logger:   logger,
+	}
+}
+
+// SendDigests отправляет сводки всем, кому они положены на момент now, и возвращает число отправленных.
+// Пользователи в тихих часах пропускаются и получат сводку при следующем запуске после их окончания.
+// Ошибка отправки одному пользователю не останавливает рассылку остальным
+func (s *DigestService) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	recipients, err := s.userRepo.ListDigestRecipients(ctx, now.Add(-s.period))
+	if err != nil {
+		return 0, fmt.Errorf("list digest recipients: %w", err)
+	}
+
+	sent := 0
+	for _, user := range recipients {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		if user.QuietHours != nil && user.QuietHours.Contains(now) {
+			continue
+		}
+
+		ok, err := s.send(ctx, user, now)
+		if err != nil {
+			s.logger.Error("failed to send review digest",
+				zap.String("user_id", user.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// send отправляет сводку пользователю. Возвращает false, если открытых PR на ревью у него уже нет
+func (s *DigestService) send(ctx context.Context, user *domain.User, now time.Time) (bool, error) {
+	prs, err := s.prRepo.GetByReviewerID(ctx, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("get user reviews: %w", err)
+	}
+
+	open := make([]*domain.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if pr.IsOpen() {
+			open = append(open, pr)
+		}
+	}
+	if len(open) == 0 {
+		return false, nil
+	}
+
+	digest := &domain.ReviewDigest{
+		User:         user,
+		PullRequests: open,
+		GeneratedAt:  now,
+	}
+	// Сводка, доставленная хотя бы в один канал, считается отправленной: иначе успешные каналы
+	// получали бы её повторно при каждой проверке, пока не восстановится неудачный
+	var partial *notify.PartialDeliveryError
+	if err := s.notifier.SendDigest(ctx, digest); err != nil {
+		if !errors.As(err, &partial) {
+			return false, fmt.Errorf("send digest: %w", err)
+		}
+		s.logger.Warn("review digest not delivered to some channels",
+			zap.String("user_id", user.ID),
+			zap.Error(partial.Err),
+		)
+	}
+
+	if err := s.userRepo.MarkDigestSent(ctx, user.ID, now); err != nil {
+		return false, fmt.Errorf("mark digest sent: %w", err)
+	}
+
+	s.logger.Info("review digest sent",
+		zap.String("user_id", user.ID),
+		zap.Int("pull_requests", len(open)),
+	)
+
+	return true, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+)
+
+// digestUserRepo возвращает одного получателя сводки и запоминает отметки об отправке
+type digestUserRepo struct {
+	repository.UserRepository
+	marked []string
+}
+
+func (r *digestUserRepo) ListDigestRecipients(context.Context, time.Time) ([]*domain.User, error) {
+	return []*domain.User{{ID: "u2", Username: "Bob"}}, nil
+}
+
+func (r *digestUserRepo) MarkDigestSent(_ context.Context, userID string, _ time.Time) error {
+	r.marked = append(r.marked, userID)
+	return nil
+}
+
+// digestPRRepo возвращает один открытый PR на ревью
+type digestPRRepo struct {
+	repository.PullRequestRepository
+}
+
+func (digestPRRepo) GetByReviewerID(context.Context, string) ([]*domain.PullRequest, error) {
+	return []*domain.PullRequest{{ID: "pr-1", Status: domain.StatusOpen}}, nil
+}
+
+// countingDigestChannel считает отправленные сводки и возвращает err
+type countingDigestChannel struct {
+	sent int
+	err  error
+}
+
+func (c *countingDigestChannel) SendDigest(context.Context, *domain.ReviewDigest) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent++
+	return nil
+}
+
+func TestSendDigests_PartialDeliveryMarksSent(t *testing.T) {
+	userRepo := &digestUserRepo{}
+	webhook := &countingDigestChannel{}
+	email := &countingDigestChannel{err: errors.New("smtp unavailable")}
+	s := NewDigestService(userRepo, digestPRRepo{}, notify.DigestNotifiers{webhook, email}, time.Hour, zap.NewNop())
+
+	sent, err := s.SendDigests(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 1, webhook.sent)
+	assert.Equal(t, []string{"u2"}, userRepo.marked)
+
+	// Ни один канал не доставил сводку - она будет отправлена при следующем запуске
+	userRepo.marked = nil
+	webhook.err = errors.New("webhook unavailable")
+	sent, err = s.SendDigests(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, userRepo.marked)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+	pkgErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+type IdempotencyService struct {
+	repo    repository.IdempotencyRepository
+	ttl     time.Duration
+	lockTTL time.Duration
+	logger  *zap.Logger
+}
+
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl, lockTTL time.Duration, logger *zap.Logger) *IdempotencyService {
+	return &IdempotencyService{
+		repo:    repo,
+		ttl:     ttl,
+		lockTTL: lockTTL,
+		logger:  logger,
+	}
+}
+
+// Begin резервирует ключ для запроса.
+// Возвращает сохранённую запись, если запрос с этим ключом уже был выполнен, и nil, если запрос нужно обработать
+func (s *IdempotencyService) Begin(ctx context.Context, key, scope, requestHash string) (*domain.IdempotencyRecord, error) {
+	if key == "" || scope == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	now := time.Now()
+	existing, err := s.repo.Reserve(ctx, &domain.IdempotencyRecord{
+		Key:         key,
+		Scope:       scope,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+		LockedUntil: now.Add(s.lockTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, pkgErrors.ErrIdempotencyKeyReused
+	}
+
+	if !existing.IsCompleted() {
+		return nil, pkgErrors.ErrRequestInProgress
+	}
+
+	s.logger.Debug("replaying idempotent response",
+		zap.String("key", key),
+		zap.String("scope", scope),
+		zap.Int("status", existing.StatusCode),
+	)
+
+	return existing, nil
+}
+
+// Complete сохраняет ответ для повторов запроса
+func (s *IdempotencyService) Complete(ctx context.Context, key, scope string, statusCode int, etag string, body []byte) error {
+	if err := s.repo.Complete(ctx, key, scope, statusCode, etag, body); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release освобождает ключ без сохранения ответа (например, после внутренней ошибки)
+func (s *IdempotencyService) Release(ctx context.Context, key, scope string) error {
+	if err := s.repo.Delete(ctx, key, scope); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет просроченные ключи и возвращает их число
+func (s *IdempotencyService) DeleteExpired(ctx context.Context) (int, error) {
+	deleted, err := s.repo.DeleteExpired(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return deleted, nil
+}
@@ -1,17 +1,26 @@
 package service
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+)
 
 // CreateTeamInput входные данные для создания команды
 type CreateTeamInput struct {
-	TeamName string
-	Members  []TeamMemberInput
+	TeamName           string
+	ParentTeamName     string // пустая строка - корневая команда
+	Members            []TeamMemberInput
+	TransferPolicy     domain.TransferPolicy // пустое значение - TransferPolicyReject
+	MinSeniorReviewers int                   // сколько из назначенных на PR ревьюеров должны быть senior
 }
 
 // TeamMemberInput данные участника команды
 type TeamMemberInput struct {
 	UserID   string
 	Username string
+	Email    string // пустая строка - оставить сохранённый адрес
 	IsActive bool
 }
 
@@ -25,6 +34,18 @@ func (i *CreateTeamInput) Validate() error {
 		return fmt.Errorf("team_name too long (max 100 characters)")
 	}
 
+	if i.ParentTeamName == i.TeamName {
+		return fmt.Errorf("team cannot be its own parent")
+	}
+
+	if err := validateMinSeniorReviewers(i.MinSeniorReviewers); err != nil {
+		return err
+	}
+
+	if i.TransferPolicy != "" && !i.TransferPolicy.IsValid() {
+		return fmt.Errorf("unknown transfer_policy: %s", i.TransferPolicy)
+	}
+
 	// Уникальность user_id внутри запроса
 	seen := make(map[string]bool)
 	for _, m := range i.Members {
@@ -34,6 +55,11 @@ func (i *CreateTeamInput) Validate() error {
 		if m.Username == "" {
 			return fmt.Errorf("username is required for all members")
 		}
+		if m.Email != "" {
+			if err := domain.ValidateEmail(m.Email); err != nil {
+				return err
+			}
+		}
 
 		if seen[m.UserID] {
 			return fmt.Errorf("duplicate user_id in request: %s", m.UserID)
@@ -44,11 +70,113 @@ func (i *CreateTeamInput) Validate() error {
 	return nil
 }
 
+// UpdateTeamInput входные данные для изменения команды
+type UpdateTeamInput struct {
+	TeamName           string
+	NewTeamName        string // пустая строка - не переименовывать
+	AddMembers         []TeamMemberInput
+	RemoveMembers      []string
+	TransferPolicy     domain.TransferPolicy // пустое значение - TransferPolicyReject
+	ParentTeamName     *string               // nil - не менять, пустая строка - сделать корневой
+	MinSeniorReviewers *int                  // nil - не менять
+	ChatWebhookURL     *string               // nil - не менять, пустая строка - удалить вебхук
+}
+
+func (i *UpdateTeamInput) Validate() error {
+	if i.TeamName == "" {
+		return fmt.Errorf("team_name is required")
+	}
+
+	if len(i.NewTeamName) > 100 {
+		return fmt.Errorf("new_team_name too long (max 100 characters)")
+	}
+
+	if i.ParentTeamName != nil && *i.ParentTeamName != "" &&
+		(*i.ParentTeamName == i.TeamName || *i.ParentTeamName == i.NewTeamName) {
+		return fmt.Errorf("team cannot be its own parent")
+	}
+
+	if i.MinSeniorReviewers != nil {
+		if err := validateMinSeniorReviewers(*i.MinSeniorReviewers); err != nil {
+			return err
+		}
+	}
+
+	if i.ChatWebhookURL != nil && *i.ChatWebhookURL != "" {
+		if err := domain.ValidateWebhookURL(*i.ChatWebhookURL); err != nil {
+			return fmt.Errorf("invalid chat_webhook_url: %w", err)
+		}
+	}
+
+	if i.TransferPolicy != "" && !i.TransferPolicy.IsValid() {
+		return fmt.Errorf("unknown transfer_policy: %s", i.TransferPolicy)
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range i.AddMembers {
+		if m.UserID == "" {
+			return fmt.Errorf("user_id is required for all members")
+		}
+		if m.Username == "" {
+			return fmt.Errorf("username is required for all members")
+		}
+		if m.Email != "" {
+			if err := domain.ValidateEmail(m.Email); err != nil {
+				return err
+			}
+		}
+
+		if seen[m.UserID] {
+			return fmt.Errorf("duplicate user_id in request: %s", m.UserID)
+		}
+		seen[m.UserID] = true
+	}
+
+	for _, userID := range i.RemoveMembers {
+		if userID == "" {
+			return fmt.Errorf("user_id is required for all removed members")
+		}
+		if seen[userID] {
+			return fmt.Errorf("user %s cannot be both added and removed", userID)
+		}
+	}
+
+	return nil
+}
+
+// AddAbsenceInput входные данные для регистрации отсутствия
+type AddAbsenceInput struct {
+	UserID   string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+func (i *AddAbsenceInput) Validate() error {
+	if i.UserID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	if i.StartsAt.IsZero() || i.EndsAt.IsZero() {
+		return fmt.Errorf("starts_at and ends_at are required")
+	}
+	if !i.EndsAt.After(i.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	if i.EndsAt.Sub(i.StartsAt) > maxAbsenceDuration {
+		return fmt.Errorf("absence is too long (max %d days)", int(maxAbsenceDuration.Hours()/24))
+	}
+	if len(i.Reason) > 200 {
+		return fmt.Errorf("reason too long (max 200 characters)")
+	}
+	return nil
+}
+
 // CreatePRInput входные данные для создания PR
 type CreatePRInput struct {
 	PullRequestID   string
 	PullRequestName string
 	AuthorID        string
+	TeamName        string // пустая строка - команда автора
 }
 
 func (i *CreatePRInput) Validate() error {
@@ -109,3 +237,31 @@ func (i *MergePRInput) Validate() error {
 	}
 	return nil
 }
+
+// ReviewerRuleInput входные данные для правила назначения ревьюера автору PR
+type ReviewerRuleInput struct {
+	TeamName   string
+	AuthorID   string
+	ReviewerID string
+	Kind       domain.ReviewerRuleKind
+}
+
+func (i *ReviewerRuleInput) Validate() error {
+	if i.TeamName == "" || i.AuthorID == "" || i.ReviewerID == "" {
+		return fmt.Errorf("team_name, author_id and reviewer_id are required")
+	}
+	if i.AuthorID == i.ReviewerID {
+		return fmt.Errorf("author cannot be a reviewer of their own PR")
+	}
+	if !i.Kind.IsValid() {
+		return fmt.Errorf("unknown kind: %s", i.Kind)
+	}
+	return nil
+}
+
+func validateMinSeniorReviewers(n int) error {
+	if n < 0 || n > domain.MaxReviewersPerPR {
+		return fmt.Errorf("min_senior_reviewers must be between 0 and %d", domain.MaxReviewersPerPR)
+	}
+	return nil
+}
@@ -5,41 +5,192 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/notify"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
 	pkgErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
 )
 
+// notifyEventTimeout ограничивает подготовку и отправку уведомления о событии PR
+const notifyEventTimeout = 30 * time.Second
+
+// maxAssignAttempts - сколько раз CreatePR подбирает ревьюеров, если выбранных успели занять до лимита открытых ревью
+const maxAssignAttempts = 3
+
 type PRService struct {
 	prRepo   repository.PullRequestRepository
 	userRepo repository.UserRepository
-	logger   *zap.Logger
-	rng      *rand.Rand
+	teamRepo repository.TeamRepository
+	ruleRepo repository.ReviewerRuleRepository
+	notifier notify.EventNotifier // nil - уведомления о событиях PR отключены
+	mode     domain.AssignmentMode
+	// rotationWindow - за какое время учитываются прошлые ревью PR автора, 0 - ротация отключена
+	rotationWindow time.Duration
+	logger         *zap.Logger
+
+	notifyWG sync.WaitGroup // отправляющиеся уведомления о событиях PR
+
+	// rngMu защищает rng: *rand.Rand не безопасен для конкурентного использования
+	rngMu sync.Mutex
+	rng   *rand.Rand
 }
 
-func NewPRService(prRepo repository.PullRequestRepository, userRepo repository.UserRepository, logger *zap.Logger) *PRService {
+func NewPRService(
+	prRepo repository.PullRequestRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	ruleRepo repository.ReviewerRuleRepository,
+	notifier notify.EventNotifier,
+	mode domain.AssignmentMode,
+	rotationWindow time.Duration,
+	logger *zap.Logger,
+) *PRService {
 	// Инициализируем генератор случайных чисел
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	return &PRService{
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		logger:   logger,
-		rng:      rng,
+		prRepo:         prRepo,
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		ruleRepo:       ruleRepo,
+		notifier:       notifier,
+		mode:           mode,
+		rotationWindow: rotationWindow,
+		logger:         logger,
+		rng:            rng,
 	}
 }
 
-// CreatePR создает новый PR с автоматическим назначением ревьюеров
-func (s *PRService) CreatePR(ctx context.Context, prID, name, authorID string) (*domain.PullRequest, error) {
+// CreatePR создает новый PR с автоматическим назначением ревьюеров из команды teamName
+// и возвращает объяснение выбора ревьюеров.
+// Если teamName пустой, используется команда автора; автор из нескольких команд должен указать её явно
+func (s *PRService) CreatePR(ctx context.Context, prID, name, authorID, teamName string) (*domain.PullRequest, *domain.AssignmentExplanation, error) {
 	// Валидация
 	if prID == "" || name == "" || authorID == "" {
+		return nil, nil, pkgErrors.ErrInvalidInput
+	}
+
+	var (
+		pr        *domain.PullRequest
+		selection *reviewerSelection
+	)
+	// Лимит открытых ревью проверяется ещё раз при сохранении: если параллельный запрос успел
+	// занять выбранного ревьюера, ревьюеры подбираются заново
+	for attempt := 1; ; attempt++ {
+		var err error
+		selection, err = s.selectReviewers(ctx, authorID, teamName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		s.logger.Info("reviewers selected",
+			zap.String("pr_id", prID),
+			zap.Strings("reviewer_ids", selection.reviewerIDs()),
+		)
+
+		// Создаем PR
+		pr = &domain.PullRequest{
+			ID:                prID,
+			Name:              name,
+			AuthorID:          authorID,
+			Status:            domain.StatusOpen,
+			AssignedReviewers: selection.reviewerIDs(),
+			CreatedAt:         time.Now(),
+			Version:           1,
+		}
+		if selection.team != nil {
+			pr.TeamID = selection.team.ID
+			pr.TeamName = selection.team.Name
+		}
+
+		err = s.prRepo.Create(ctx, pr, pr.AssignedReviewers)
+		if err == nil {
+			break
+		}
+
+		switch {
+		case errors.Is(err, repository.ErrAtCapacity) && attempt < maxAssignAttempts:
+			s.logger.Info("selected reviewer reached open review limit, selecting again",
+				zap.String("pr_id", prID),
+				zap.Error(err),
+			)
+			continue
+		case errors.Is(err, repository.ErrAtCapacity):
+			return nil, nil, fmt.Errorf("%w: %v", pkgErrors.ErrReviewerAtCapacity, err)
+		case errors.Is(err, repository.ErrAlreadyExists):
+			return nil, nil, pkgErrors.ErrPRExists
+		}
+		s.logger.Error("failed to create PR",
+			zap.String("pr_id", prID),
+			zap.Error(err),
+		)
+		return nil, nil, fmt.Errorf("create PR: %w", err)
+	}
+	reviewerIDs := pr.AssignedReviewers
+
+	s.logger.Info("PR created",
+		zap.String("pr_id", prID),
+		zap.String("author_id", authorID),
+		zap.Int("reviewers_count", len(reviewerIDs)),
+	)
+
+	if len(reviewerIDs) > 0 {
+		s.notifyPREvent(ctx, domain.PREventAssigned, pr, reviewerIDs, "")
+	}
+
+	return pr, selection.explanation, nil
+}
+
+// PreviewPR подбирает ревьюеров так же, как CreatePR, но ничего не сохраняет.
+// Возвращает предлагаемых ревьюеров и всех подходящих кандидатов
+func (s *PRService) PreviewPR(ctx context.Context, authorID, teamName string) (*domain.AssignmentPreview, error) {
+	if authorID == "" {
 		return nil, pkgErrors.ErrInvalidInput
 	}
 
+	selection, err := s.selectReviewers(ctx, authorID, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := selection.candidates
+	if pool == nil {
+		pool = []*domain.User{}
+	}
+
+	return &domain.AssignmentPreview{
+		AuthorID:          authorID,
+		ProposedReviewers: selection.reviewerIDs(),
+		EligiblePool:      pool,
+		Assignment:        selection.explanation,
+	}, nil
+}
+
+// reviewerSelection - результат подбора ревьюеров для нового PR
+type reviewerSelection struct {
+	team        *domain.Team   // nil - автор не состоит в командах
+	candidates  []*domain.User // подходящие кандидаты в порядке приоритета
+	reviewers   []*domain.User
+	explanation *domain.AssignmentExplanation
+}
+
+func (r *reviewerSelection) reviewerIDs() []string {
+	ids := make([]string, len(r.reviewers))
+	for i, u := range r.reviewers {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+// selectReviewers подбирает до MaxReviewersPerPR ревьюеров для нового PR автора из команды teamName
+func (s *PRService) selectReviewers(ctx context.Context, authorID, teamName string) (*reviewerSelection, error) {
 	// Получаем автора и его команду
 	author, err := s.userRepo.GetByID(ctx, authorID)
 	if err != nil {
@@ -53,89 +204,166 @@ func (s *PRService) CreatePR(ctx context.Context, prID, name, authorID string) (
 		return nil, fmt.Errorf("get author: %w", err)
 	}
 
-	s.logger.Debug("author found",
-		zap.String("author_id", author.ID),
-		zap.Int("team_id", author.TeamID),
-	)
-
-	// Получаем активных кандидатов из команды автора (исключая автора)
-	candidates, err := s.userRepo.GetActiveUsersByTeamID(ctx, author.TeamID, []string{authorID})
+	team, err := s.resolvePRTeam(ctx, author.ID, teamName)
 	if err != nil {
-		s.logger.Error("failed to get reviewer candidates",
-			zap.Int("team_id", author.TeamID),
-			zap.Error(err),
-		)
-		return nil, fmt.Errorf("get candidates: %w", err)
+		return nil, err
 	}
 
-	s.logger.Debug("reviewers candidates found",
-		zap.Int("count", len(candidates)),
+	// Автор без команды: PR создаётся без ревьюеров
+	var (
+		candidates []*domain.User
+		preferred  []string
 	)
+	explanation := &domain.AssignmentExplanation{
+		Strategy: s.mode,
+		Excluded: []*domain.Exclusion{},
+	}
+	if team != nil {
+		explanation.TeamName = team.Name
 
-	// Выбираем до 2 случайных ревьюеров
-	reviewerIDs := s.selectRandomReviewers(candidates, 2)
+		s.logger.Debug("author found",
+			zap.String("author_id", author.ID),
+			zap.Int("team_id", team.ID),
+		)
 
-	s.logger.Info("reviewers selected",
-		zap.String("pr_id", prID),
-		zap.Strings("reviewer_ids", reviewerIDs),
-	)
+		// Правила команды для автора: запрещённые ревьюеры исключаются, предпочитаемые идут первыми
+		var forbidden []string
+		preferred, forbidden, err = s.authorRules(ctx, team.ID, authorID)
+		if err != nil {
+			return nil, err
+		}
 
-	// Создаем PR
-	now := time.Now()
-	pr := &domain.PullRequest{
-		ID:                prID,
-		Name:              name,
-		AuthorID:          authorID,
-		Status:            domain.StatusOpen,
-		AssignedReviewers: reviewerIDs,
-		CreatedAt:         now,
+		// Отбираем кандидатов из команды PR (исключая автора и запрещённых ревьюеров)
+		excluded := exclusionReasons(forbidden, domain.ExclusionForbidden)
+		excluded[authorID] = domain.ExclusionAuthor
+		candidates, err = s.candidatePool(ctx, team.ID, excluded, explanation)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.prRepo.Create(ctx, pr, reviewerIDs); err != nil {
-		if errors.Is(err, repository.ErrAlreadyExists) {
-			return nil, pkgErrors.ErrPRExists
-		}
-		s.logger.Error("failed to create PR",
-			zap.String("pr_id", prID),
+	// Выбираем до 2 ревьюеров с учётом правила команды о senior-ревьюерах
+	minSeniors := 0
+	if team != nil {
+		minSeniors = team.MinSeniorReviewers
+	}
+	recent, err := s.recentReviews(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := domain.PreferFirst(s.rankCandidates(candidates, recent), preferred)
+	reviewers := domain.PickReviewers(ranked, domain.MaxReviewersPerPR, minSeniors)
+	explanation.Selected = domain.ExplainPicks(reviewers, ranked, domain.MaxReviewersPerPR, preferred, s.mode, recent)
+	explanation.Shortfall = domain.ExplainShortfall(team != nil, len(reviewers), explanation.Excluded)
+	if explanation.Shortfall == domain.ShortfallAtCapacity {
+		s.logger.Warn("team reviewers are at their open review limit",
+			zap.String("author_id", authorID),
+			zap.String("team_name", team.Name),
+			zap.Int("assigned", len(reviewers)),
+			zap.Int("at_capacity", domain.CountExclusions(explanation.Excluded, domain.ExclusionAtCapacity)),
+		)
+	}
+
+	if seniors := domain.CountSeniors(reviewers); seniors < min(minSeniors, domain.MaxReviewersPerPR) {
+		s.logger.Warn("not enough senior reviewers available",
+			zap.String("author_id", authorID),
+			zap.String("team_name", team.Name),
+			zap.Int("required", minSeniors),
+			zap.Int("assigned", seniors),
+		)
+	}
+
+	return &reviewerSelection{
+		team:        team,
+		candidates:  ranked,
+		reviewers:   reviewers,
+		explanation: explanation,
+	}, nil
+}
+
+// resolvePRTeam выбирает команду PR среди команд автора. Возвращает nil, если автор не состоит в командах
+func (s *PRService) resolvePRTeam(ctx context.Context, authorID, teamName string) (*domain.Team, error) {
+	teams, err := s.userRepo.GetTeams(ctx, authorID)
+	if err != nil {
+		s.logger.Error("failed to get author teams",
+			zap.String("author_id", authorID),
 			zap.Error(err),
 		)
-		return nil, fmt.Errorf("create PR: %w", err)
+		return nil, fmt.Errorf("get author teams: %w", err)
 	}
 
-	s.logger.Info("PR created",
-		zap.String("pr_id", prID),
-		zap.String("author_id", authorID),
-		zap.Int("reviewers_count", len(reviewerIDs)),
-	)
+	if teamName != "" {
+		for _, team := range teams {
+			if team.Name == teamName {
+				return team, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: author %s is not a member of team %s", pkgErrors.ErrInvalidInput, authorID, teamName)
+	}
 
-	return pr, nil
+	switch len(teams) {
+	case 0:
+		return nil, nil
+	case 1:
+		return teams[0], nil
+	}
+
+	names := make([]string, len(teams))
+	for i, team := range teams {
+		names[i] = team.Name
+	}
+	return nil, fmt.Errorf("%w: author %s belongs to several teams (%s), team_name is required",
+		pkgErrors.ErrInvalidInput, authorID, strings.Join(names, ", "))
 }
 
-// SelectRandomReviewers выбирает случайных ревьюеров из списка кандидатов
-func (s *PRService) selectRandomReviewers(candidates []*domain.User, maxCount int) []string {
+// rankCandidates упорядочивает кандидатов по приоритету назначения с учётом их весов.
+// Кандидаты, которые недавно ревьюили PR автора (recent), идут после остальных: чем больше ревью, тем позже.
+// В режиме working_hours сначала идут те, у кого сейчас рабочее время, затем те, у кого оно начнётся раньше;
+// при равенстве порядок случайный
+func (s *PRService) rankCandidates(candidates []*domain.User, recent map[string]int) []*domain.User {
 	n := len(candidates)
-	if n == 0 {
-		return []string{}
-	}
 
-	// Определяем сколько ревьюеров выбрать
-	count := n
-	if count > maxCount {
-		count = maxCount
+	// Взвешенная выборка без возвращения: каждому кандидату достаётся случайный ключ ~ Exp(weight),
+	// меньший ключ - раньше. Кандидат с весом 2 оказывается первым вдвое чаще кандидата с весом 1
+	keys := make(map[string]float64, n)
+	s.rngMu.Lock()
+	for _, c := range candidates {
+		keys[c.ID] = s.rng.ExpFloat64() / c.EffectiveReviewWeight()
 	}
+	s.rngMu.Unlock()
 
-	perm := s.rng.Perm(n)
+	ordered := append([]*domain.User(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return keys[ordered[i].ID] < keys[ordered[j].ID]
+	})
 
-	result := make([]string, count)
-	for i := 0; i < count; i++ {
-		result[i] = candidates[perm[i]].ID
+	if len(recent) > 0 {
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return recent[ordered[i].ID] < recent[ordered[j].ID]
+		})
+	}
+
+	if s.mode == domain.AssignmentModeWorkingHours {
+		now := time.Now()
+		wait := make(map[string]time.Duration, n)
+		for _, c := range ordered {
+			// Пользователь без расписания доступен в любое время
+			if c.Schedule != nil {
+				wait[c.ID] = c.Schedule.UntilWorking(now)
+			}
+		}
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return wait[ordered[i].ID] < wait[ordered[j].ID]
+		})
 	}
 
-	return result
+	return ordered
 }
 
-// MergePR идемпотентно мержит PR
-func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullRequest, error) {
+// MergePR идемпотентно мержит PR.
+// Если expectedVersion != 0, PR мержится только при совпадении версии
+func (s *PRService) MergePR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error) {
 	if prID == "" {
 		return nil, pkgErrors.ErrInvalidInput
 	}
@@ -153,6 +381,11 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullReque
 		return nil, fmt.Errorf("get PR: %w", err)
 	}
 
+	// Версию проверяем и у смерженного PR: клиент с устаревшей версией не должен получать успех
+	if expectedVersion != 0 && pr.Version != expectedVersion {
+		return nil, pkgErrors.ErrVersionConflict
+	}
+
 	if pr.Status == domain.StatusMerged {
 		s.logger.Debug("PR already merged",
 			zap.String("pr_id", prID),
@@ -162,7 +395,11 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullReque
 
 	// Обновляем статус
 	now := time.Now()
-	if err := s.prRepo.UpdateStatus(ctx, prID, domain.StatusMerged, &now); err != nil {
+	merged, err := s.prRepo.UpdateStatus(ctx, prID, domain.StatusMerged, &now, expectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return nil, pkgErrors.ErrVersionConflict
+		}
 		s.logger.Error("failed to merge PR",
 			zap.String("pr_id", prID),
 			zap.Error(err),
@@ -170,9 +407,13 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullReque
 		return nil, fmt.Errorf("merge PR: %w", err)
 	}
 
-	s.logger.Info("PR merged",
-		zap.String("pr_id", prID),
-	)
+	// PR мог смержить параллельный запрос: уведомляет только тот, кто сменил статус
+	if merged {
+		s.logger.Info("PR merged",
+			zap.String("pr_id", prID),
+		)
+		s.notifyPREvent(ctx, domain.PREventMerged, pr, pr.AssignedReviewers, "")
+	}
 
 	// Получаем обновленный PR
 	pr, err = s.prRepo.GetByID(ctx, prID)
@@ -183,35 +424,82 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullReque
 	return pr, nil
 }
 
-func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (string, *domain.PullRequest, error) {
+// ReassignReviewer заменяет ревьюера на newReviewerID или, если он не указан, на участника команды PR,
+// выбранного с учётом режима назначения, и возвращает объяснение выбора замены.
+// Если expectedVersion != 0, замена выполняется только при совпадении версии PR
+func (s *PRService) ReassignReviewer(
+	ctx context.Context,
+	prID, oldReviewerID, newReviewerID string,
+	expectedVersion int,
+) (string, *domain.PullRequest, *domain.AssignmentExplanation, error) {
 	if prID == "" || oldReviewerID == "" {
-		return "", nil, pkgErrors.ErrInvalidInput
+		return "", nil, nil, pkgErrors.ErrInvalidInput
 	}
 
-	// Получаем PR
-	pr, err := s.prRepo.GetByID(ctx, prID)
+	pr, err := s.getOpenPR(ctx, prID, expectedVersion)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return "", nil, pkgErrors.ErrNotFound
-		}
-		s.logger.Error("failed to get PR",
-			zap.String("pr_id", prID),
-			zap.Error(err),
-		)
-		return "", nil, fmt.Errorf("get PR: %w", err)
-	}
-
-	// Проверяем статус PR
-	if pr.Status == domain.StatusMerged {
-		return "", nil, pkgErrors.ErrPRMerged
+		return "", nil, nil, err
 	}
 
 	// Проверяем, что старый ревьюер назначен на PR
 	if !contains(pr.AssignedReviewers, oldReviewerID) {
-		return "", nil, pkgErrors.ErrNotAssigned
+		return "", nil, nil, pkgErrors.ErrNotAssigned
 	}
 
-	// Получаем команду заменяемого ревьюера
+	// Замена на конкретного коллегу: автоматический выбор не выполняется
+	if newReviewerID != "" {
+		newReviewer, err := s.validateManualReviewer(ctx, pr, newReviewerID)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		if err := s.checkReviewerAvailable(ctx, pr, newReviewer); err != nil {
+			return "", nil, nil, err
+		}
+
+		// Правило команды о senior-ревьюерах действует и при замене на конкретного коллегу
+		if !newReviewer.IsSenior() {
+			oldReviewer, err := s.userRepo.GetByID(ctx, oldReviewerID)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				return "", nil, nil, fmt.Errorf("get old reviewer: %w", err)
+			}
+			if err == nil {
+				seniorRequired, err := s.seniorReplacementRequired(ctx, pr, oldReviewer, pr.TeamID)
+				if err != nil {
+					return "", nil, nil, err
+				}
+				if seniorRequired {
+					return "", nil, nil, fmt.Errorf("%w: %s is not senior", pkgErrors.ErrSeniorReviewerRequired, newReviewerID)
+				}
+			}
+		}
+
+		explanation := &domain.AssignmentExplanation{
+			TeamName: pr.TeamName,
+			Strategy: s.mode,
+			Excluded: []*domain.Exclusion{},
+			Selected: []*domain.ReviewerChoice{{UserID: newReviewerID, Reason: domain.SelectionRequested}},
+		}
+		return s.replaceReviewer(ctx, pr, oldReviewerID, newReviewerID, explanation)
+	}
+
+	newReviewerID, explanation, err := s.selectReplacement(ctx, pr, oldReviewerID)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return s.replaceReviewer(ctx, pr, oldReviewerID, newReviewerID, explanation)
+}
+
+// selectReplacement выбирает участника команды PR на место ревьюера oldReviewerID
+// с учётом режима назначения, правил команды и ротации
+func (s *PRService) selectReplacement(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	oldReviewerID string,
+) (string, *domain.AssignmentExplanation, error) {
+	prID := pr.ID
+
 	oldReviewer, err := s.userRepo.GetByID(ctx, oldReviewerID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -220,59 +508,673 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID st
 		return "", nil, fmt.Errorf("get old reviewer: %w", err)
 	}
 
+	// Замена ищется в команде PR; если команда PR удалена - в основной команде заменяемого ревьюера
+	teamID := pr.TeamID
+	if teamID == 0 {
+		teamID = oldReviewer.TeamID
+	}
+
 	s.logger.Debug("old reviewer found",
 		zap.String("reviewer_id", oldReviewer.ID),
-		zap.Int("team_id", oldReviewer.TeamID),
+		zap.Int("team_id", teamID),
 	)
 
-	// Список исключаемых пользователей (уже назначенные ревьюеры и автор)
-	excludeUserIDs := append([]string{}, pr.AssignedReviewers...)
-	excludeUserIDs = append(excludeUserIDs, pr.AuthorID)
+	// Правила команды для автора PR
+	preferred, forbidden, err := s.authorRules(ctx, teamID, pr.AuthorID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Исключаемые пользователи: запрещённые для автора ревьюеры, уже назначенные и автор
+	excluded := exclusionReasons(forbidden, domain.ExclusionForbidden)
+	for _, id := range pr.AssignedReviewers {
+		excluded[id] = domain.ExclusionAlreadyAssigned
+	}
+	excluded[pr.AuthorID] = domain.ExclusionAuthor
 
-	// Получаем активных кандидатов из команды старого ревьюера (исключая уже назначенных и автора)
-	candidates, err := s.userRepo.GetActiveUsersByTeamID(ctx, oldReviewer.TeamID, excludeUserIDs)
+	explanation := &domain.AssignmentExplanation{
+		TeamName: pr.TeamName,
+		Strategy: s.mode,
+		Excluded: []*domain.Exclusion{},
+	}
+	candidates, err := s.candidatePool(ctx, teamID, excluded, explanation)
 	if err != nil {
-		s.logger.Error("failed to get replacement candidates",
-			zap.Int("team_id", oldReviewer.TeamID),
-			zap.Error(err),
-		)
-		return "", nil, fmt.Errorf("get candidates: %w", err)
+		return "", nil, err
 	}
 
-	// Выбираем случайного нового ревьюера
-	newReviewerID := s.selectRandomReviewer(candidates)
-	if newReviewerID == "" {
+	// Если заменяется senior и без него правило команды нарушится, замена тоже должна быть senior
+	seniorRequired, err := s.seniorReplacementRequired(ctx, pr, oldReviewer, teamID)
+	if err != nil {
+		return "", nil, err
+	}
+	if seniorRequired {
+		seniors := make([]*domain.User, 0, len(candidates))
+		for _, c := range candidates {
+			if c.IsSenior() {
+				seniors = append(seniors, c)
+			} else {
+				explanation.Excluded = append(explanation.Excluded,
+					&domain.Exclusion{UserID: c.ID, Reason: domain.ExclusionNotSenior})
+			}
+		}
+		if len(seniors) == 0 && len(candidates) > 0 {
+			s.logger.Warn("no senior replacement candidates available",
+				zap.String("pr_id", prID),
+				zap.String("old_reviewer_id", oldReviewerID),
+				zap.Int("team_id", teamID),
+			)
+			return "", nil, fmt.Errorf("%w: team rule requires a senior reviewer, but no senior candidate is available",
+				pkgErrors.ErrNoCandidate)
+		}
+		candidates = seniors
+		explanation.Candidates = len(seniors)
+	}
+
+	// Выбираем нового ревьюера
+	recent, err := s.recentReviews(ctx, pr.AuthorID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ranked := domain.PreferFirst(s.rankCandidates(candidates, recent), preferred)
+	if len(ranked) == 0 {
 		s.logger.Warn("no replacement candidates available",
 			zap.String("pr_id", prID),
 			zap.String("old_reviewer_id", oldReviewerID),
-			zap.Int("team_id", oldReviewer.TeamID),
+			zap.Int("team_id", teamID),
 		)
-		return "", nil, pkgErrors.ErrNoCandidate
+		return "", nil, noCandidateError(explanation)
 	}
 
+	newReviewerID := ranked[0].ID
+	explanation.Selected = domain.ExplainPicks(ranked[:1], ranked, 1, preferred, s.mode, recent)
+	if seniorRequired && explanation.Selected[0].Reason != domain.SelectionPreferRule {
+		explanation.Selected[0].Reason = domain.SelectionSeniorRule
+	}
+
+	return newReviewerID, explanation, nil
+}
+
+// replaceReviewer заменяет ревьюера в PR и возвращает обновлённый PR
+func (s *PRService) replaceReviewer(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	oldReviewerID, newReviewerID string,
+	explanation *domain.AssignmentExplanation,
+) (string, *domain.PullRequest, *domain.AssignmentExplanation, error) {
+	prID := pr.ID
+
 	s.logger.Info("new reviewer selected",
 		zap.String("pr_id", prID),
 		zap.String("old_reviewer_id", oldReviewerID),
 		zap.String("new_reviewer_id", newReviewerID),
+		zap.String("reason", string(explanation.Selected[0].Reason)),
 	)
 
-	// Заменить ревьюера в PR
-	if err := s.prRepo.ReplaceReviewer(ctx, prID, oldReviewerID, newReviewerID); err != nil {
+	// Заменить ревьюера в PR. Версия, прочитанная выше, защищает от параллельной замены.
+	// Замену инициирует уходящий ревьюер, он и записывается в журнал как actor
+	if err := s.prRepo.ReplaceReviewer(ctx, prID, oldReviewerID, newReviewerID, oldReviewerID, pr.Version); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionMismatch):
+			return "", nil, nil, pkgErrors.ErrVersionConflict
+		case errors.Is(err, repository.ErrConflict):
+			return "", nil, nil, pkgErrors.ErrPRMerged
+		case errors.Is(err, repository.ErrNotFound):
+			return "", nil, nil, pkgErrors.ErrNotAssigned
+		case errors.Is(err, repository.ErrAtCapacity):
+			return "", nil, nil, fmt.Errorf("%w: %v", pkgErrors.ErrReviewerAtCapacity, err)
+		}
 		s.logger.Error("failed to replace reviewer",
 			zap.String("pr_id", prID),
 			zap.Error(err),
 		)
-		return "", nil, fmt.Errorf("replace reviewer: %w", err)
+		return "", nil, nil, fmt.Errorf("replace reviewer: %w", err)
 	}
 
-	// Получаем обновленный PR
-	pr, err = s.prRepo.GetByID(ctx, prID)
+	s.notifyPREvent(ctx, domain.PREventReassigned, pr, []string{newReviewerID}, oldReviewerID)
+
+	updated, err := s.getUpdatedPR(ctx, prID)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return newReviewerID, updated, explanation, nil
+}
+
+// AddReviewer вручную назначает ревьюера на PR от имени actorID.
+// Ревьюер должен быть активным участником команды PR и не быть автором, а на PR должно быть меньше MaxReviewersPerPR ревьюеров.
+// Если expectedVersion != 0, назначение выполняется только при совпадении версии PR
+func (s *PRService) AddReviewer(ctx context.Context, prID, userID, actorID string, expectedVersion int) (*domain.PullRequest, error) {
+	if prID == "" || userID == "" || actorID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	pr, err := s.getOpenPR(ctx, prID, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.validateManualReviewer(ctx, pr, userID); err != nil {
+		return nil, err
+	}
+
+	// Параллельное добавление отсекается проверкой версии PR в репозитории
+	if len(pr.AssignedReviewers) >= domain.MaxReviewersPerPR {
+		return nil, fmt.Errorf("%w: %d", pkgErrors.ErrTooManyReviewers, domain.MaxReviewersPerPR)
+	}
+
+	if err := s.prRepo.AddReviewer(ctx, prID, userID, actorID, pr.Version); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionMismatch):
+			return nil, pkgErrors.ErrVersionConflict
+		case errors.Is(err, repository.ErrConflict):
+			return nil, pkgErrors.ErrPRMerged
+		case errors.Is(err, repository.ErrAlreadyExists):
+			return nil, pkgErrors.ErrAlreadyAssigned
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, pkgErrors.ErrNotFound
+		case errors.Is(err, repository.ErrAtCapacity):
+			return nil, fmt.Errorf("%w: %v", pkgErrors.ErrReviewerAtCapacity, err)
+		}
+		s.logger.Error("failed to add reviewer",
+			zap.String("pr_id", prID),
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("add reviewer: %w", err)
+	}
+
+	s.logger.Info("reviewer added",
+		zap.String("pr_id", prID),
+		zap.String("user_id", userID),
+		zap.String("actor_id", actorID),
+	)
+
+	s.notifyPREvent(ctx, domain.PREventAssigned, pr, []string{userID}, "")
+
+	return s.getUpdatedPR(ctx, prID)
+}
+
+// RemoveReviewer снимает ревьюера с PR без замены от имени actorID.
+// Если expectedVersion != 0, изменение выполняется только при совпадении версии PR
+func (s *PRService) RemoveReviewer(ctx context.Context, prID, userID, actorID string, expectedVersion int) (*domain.PullRequest, error) {
+	if prID == "" || userID == "" || actorID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	pr, err := s.getOpenPR(ctx, prID, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if !contains(pr.AssignedReviewers, userID) {
+		return nil, pkgErrors.ErrNotAssigned
+	}
+
+	if err := s.prRepo.RemoveReviewer(ctx, prID, userID, actorID, pr.Version); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionMismatch):
+			return nil, pkgErrors.ErrVersionConflict
+		case errors.Is(err, repository.ErrConflict):
+			return nil, pkgErrors.ErrPRMerged
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, pkgErrors.ErrNotAssigned
+		}
+		s.logger.Error("failed to remove reviewer",
+			zap.String("pr_id", prID),
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("remove reviewer: %w", err)
+	}
+
+	s.logger.Info("reviewer removed",
+		zap.String("pr_id", prID),
+		zap.String("user_id", userID),
+		zap.String("actor_id", actorID),
+	)
+
+	return s.getUpdatedPR(ctx, prID)
+}
+
+// SubmitReview отмечает, что ревьюер отправил ревью по PR. После этого его назначение не нарушает SLA
+func (s *PRService) SubmitReview(ctx context.Context, prID, userID string) (*domain.PullRequest, error) {
+	if prID == "" || userID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	pr, err := s.getOpenPR(ctx, prID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !contains(pr.AssignedReviewers, userID) {
+		return nil, pkgErrors.ErrNotAssigned
+	}
+
+	if err := s.prRepo.MarkReviewed(ctx, prID, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotAssigned
+		}
+		return nil, fmt.Errorf("mark reviewed: %w", err)
+	}
+
+	s.logger.Info("review submitted",
+		zap.String("pr_id", prID),
+		zap.String("user_id", userID),
+	)
+
+	return pr, nil
+}
+
+// GetEscalations возвращает события эскалации просроченных ревью PR
+func (s *PRService) GetEscalations(ctx context.Context, prID string) ([]*domain.ReviewEscalation, error) {
+	if prID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	if _, err := s.prRepo.GetByID(ctx, prID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("get PR: %w", err)
+	}
+
+	escalations, err := s.prRepo.ListEscalations(ctx, prID)
 	if err != nil {
-		return "", nil, fmt.Errorf("get updated PR: %w", err)
+		return nil, fmt.Errorf("list escalations: %w", err)
+	}
+
+	return escalations, nil
+}
+
+// getOpenPR возвращает PR, если он открыт и его версия совпадает с expectedVersion (если != 0)
+func (s *PRService) getOpenPR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		s.logger.Error("failed to get PR",
+			zap.String("pr_id", prID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get PR: %w", err)
 	}
 
-	return newReviewerID, pr, nil
+	if pr.Status == domain.StatusMerged {
+		return nil, pkgErrors.ErrPRMerged
+	}
 
+	if expectedVersion != 0 && pr.Version != expectedVersion {
+		return nil, pkgErrors.ErrVersionConflict
+	}
+
+	return pr, nil
+}
+
+// getUpdatedPR перечитывает PR после изменения
+func (s *PRService) getUpdatedPR(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated PR: %w", err)
+	}
+	return pr, nil
+}
+
+// notifyPREvent в фоне уведомляет о событии PR. Данные PR и пользователей читаются заново,
+// чтобы уведомление отражало сохранённое состояние, и только если событие нужно хотя бы одному каналу.
+// Ошибки уведомления только логируются
+func (s *PRService) notifyPREvent(ctx context.Context, kind domain.PREventKind, pr *domain.PullRequest, reviewerIDs []string, replacedID string) {
+	if s.notifier == nil {
+		return
+	}
+	// Без команды каналам, зависящим от её настроек, событие не нужно
+	if pr.TeamID == 0 && !s.notifier.Wants(kind, nil) {
+		return
+	}
+
+	// Уведомление не должно прерываться вместе с запросом, который его вызвал
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyEventTimeout)
+	s.notifyWG.Add(1)
+	go func() {
+		defer s.notifyWG.Done()
+		defer cancel()
+
+		if err := s.sendPREvent(ctx, kind, pr.ID, pr.TeamID, reviewerIDs, replacedID); err != nil {
+			s.logger.Error("failed to notify about PR event",
+				zap.String("pr_id", pr.ID),
+				zap.String("event", string(kind)),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// Wait ждёт завершения отправки начатых уведомлений о событиях PR
+func (s *PRService) Wait() {
+	s.notifyWG.Wait()
+}
+
+func (s *PRService) sendPREvent(ctx context.Context, kind domain.PREventKind, prID string, teamID int, reviewerIDs []string, replacedID string) error {
+	var team *domain.Team
+	if teamID != 0 {
+		var err error
+		if team, err = s.teamRepo.GetByID(ctx, teamID); err != nil {
+			return fmt.Errorf("get team: %w", err)
+		}
+	}
+	if !s.notifier.Wants(kind, team) {
+		return nil
+	}
+
+	event, err := s.buildPREvent(ctx, kind, prID, team, reviewerIDs, replacedID)
+	if err != nil {
+		return err
+	}
+	return s.notifier.NotifyPREvent(ctx, event)
+}
+
+func (s *PRService) buildPREvent(ctx context.Context, kind domain.PREventKind, prID string, team *domain.Team, reviewerIDs []string, replacedID string) (*domain.PREvent, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get PR: %w", err)
+	}
+
+	event := &domain.PREvent{
+		Kind:       kind,
+		PR:         pr,
+		Team:       team,
+		Reviewers:  make([]*domain.User, 0, len(reviewerIDs)),
+		OccurredAt: time.Now(),
+	}
+
+	if event.Author, err = s.userRepo.GetByID(ctx, pr.AuthorID); err != nil {
+		return nil, fmt.Errorf("get author: %w", err)
+	}
+	for _, id := range reviewerIDs {
+		reviewer, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get reviewer %s: %w", id, err)
+		}
+		event.Reviewers = append(event.Reviewers, reviewer)
+	}
+	if replacedID != "" {
+		if event.Replaced, err = s.userRepo.GetByID(ctx, replacedID); err != nil {
+			return nil, fmt.Errorf("get replaced reviewer: %w", err)
+		}
+	}
+
+	return event, nil
+}
+
+// validateManualReviewer проверяет, что пользователя можно вручную назначить ревьюером PR:
+// он существует, не автор, ещё не назначен, активен, состоит в команде PR и не запрещён автору правилом команды.
+// Возвращает найденного пользователя
+func (s *PRService) validateManualReviewer(ctx context.Context, pr *domain.PullRequest, userID string) (*domain.User, error) {
+	if userID == pr.AuthorID {
+		return nil, pkgErrors.ErrAuthorAsReviewer
+	}
+
+	if contains(pr.AssignedReviewers, userID) {
+		return nil, pkgErrors.ErrAlreadyAssigned
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user %s", pkgErrors.ErrNotFound, userID)
+		}
+		return nil, fmt.Errorf("get reviewer: %w", err)
+	}
+
+	if !user.IsActive {
+		return nil, pkgErrors.ErrReviewerInactive
+	}
+
+	// Если команда PR удалена, состав команды не проверяется
+	if pr.TeamName != "" && !contains(user.Teams, pr.TeamName) {
+		return nil, fmt.Errorf("%w: user %s is not a member of team %s", pkgErrors.ErrNotTeamMember, userID, pr.TeamName)
+	}
+
+	_, forbidden, err := s.authorRules(ctx, pr.TeamID, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if contains(forbidden, userID) {
+		return nil, fmt.Errorf("%w: %s for author %s", pkgErrors.ErrReviewerForbidden, userID, pr.AuthorID)
+	}
+
+	return user, nil
+}
+
+// checkReviewerAvailable проверяет, что пользователь сейчас не отсутствует и не достиг лимита открытых ревью.
+// Данные берутся из пула команды PR, а если она удалена - из основной команды пользователя
+func (s *PRService) checkReviewerAvailable(ctx context.Context, pr *domain.PullRequest, user *domain.User) error {
+	teamID := pr.TeamID
+	if teamID == 0 {
+		teamID = user.TeamID
+	}
+	if teamID == 0 {
+		return nil
+	}
+
+	pool, err := s.userRepo.GetAssignmentPool(ctx, teamID)
+	if err != nil {
+		s.logger.Error("failed to get assignment pool",
+			zap.Int("team_id", teamID),
+			zap.Error(err),
+		)
+		return fmt.Errorf("get assignment pool: %w", err)
+	}
+
+	for _, m := range pool {
+		if m.User.ID != user.ID {
+			continue
+		}
+		if m.IsAbsent {
+			return fmt.Errorf("%w: %s", pkgErrors.ErrReviewerAbsent, user.ID)
+		}
+		if m.User.MaxOpenReviews != nil && m.OpenReviews >= *m.User.MaxOpenReviews {
+			return fmt.Errorf("%w: %s has %d open reviews", pkgErrors.ErrReviewerAtCapacity, user.ID, m.OpenReviews)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// recentReviews возвращает, сколько недавних PR автора ревьюит каждый пользователь.
+// Если ротация отключена, возвращает nil
+func (s *PRService) recentReviews(ctx context.Context, authorID string) (map[string]int, error) {
+	if s.rotationWindow <= 0 {
+		return nil, nil
+	}
+
+	recent, err := s.prRepo.CountRecentReviews(ctx, authorID, time.Now().Add(-s.rotationWindow))
+	if err != nil {
+		s.logger.Error("failed to get recent reviews",
+			zap.String("author_id", authorID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get recent reviews: %w", err)
+	}
+
+	return recent, nil
+}
+
+// authorRules возвращает предпочитаемых и запрещённых для автора ревьюеров по правилам команды
+func (s *PRService) authorRules(ctx context.Context, teamID int, authorID string) (preferred, forbidden []string, err error) {
+	if teamID == 0 {
+		return nil, nil, nil
+	}
+
+	rules, err := s.ruleRepo.ListByAuthor(ctx, teamID, authorID)
+	if err != nil {
+		s.logger.Error("failed to get reviewer rules",
+			zap.Int("team_id", teamID),
+			zap.String("author_id", authorID),
+			zap.Error(err),
+		)
+		return nil, nil, fmt.Errorf("get reviewer rules: %w", err)
+	}
+
+	preferred, forbidden = domain.SplitReviewerRules(rules)
+	return preferred, forbidden, nil
+}
+
+// seniorReplacementRequired проверяет, нужно ли заменить ревьюера senior-ревьюером,
+// чтобы на PR осталось не меньше senior-ревьюеров, чем требует правило команды
+func (s *PRService) seniorReplacementRequired(ctx context.Context, pr *domain.PullRequest, oldReviewer *domain.User, teamID int) (bool, error) {
+	if !oldReviewer.IsSenior() || teamID == 0 {
+		return false, nil
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get team: %w", err)
+	}
+
+	required := min(team.MinSeniorReviewers, domain.MaxReviewersPerPR)
+	if required == 0 {
+		return false, nil
+	}
+
+	seniors := 0
+	for _, id := range pr.AssignedReviewers {
+		if id == oldReviewer.ID {
+			continue
+		}
+		reviewer, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return false, fmt.Errorf("get reviewer: %w", err)
+		}
+		if reviewer.IsSenior() {
+			seniors++
+		}
+	}
+
+	return seniors < required, nil
+}
+
+// candidatePool отбирает кандидатов в ревьюеры из участников команды и записывает
+// размер команды и исключённых участников в объяснение назначения.
+// Если в команде подходящих кандидатов нет, они ищутся в ближайшей родительской команде, где они есть
+func (s *PRService) candidatePool(
+	ctx context.Context,
+	teamID int,
+	excluded map[string]domain.ExclusionReason,
+	explanation *domain.AssignmentExplanation,
+) ([]*domain.User, error) {
+	candidates, err := s.teamCandidates(ctx, teamID, excluded, explanation)
+	if err != nil || len(candidates) > 0 || teamID == 0 {
+		return candidates, err
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return candidates, nil
+		}
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	// Проверка посещённых команд защищает от зацикливания на некорректных данных
+	visited := map[int]bool{teamID: true}
+	for len(candidates) == 0 && team.ParentID != 0 && !visited[team.ParentID] {
+		team, err = s.teamRepo.GetByID(ctx, team.ParentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				break
+			}
+			return nil, fmt.Errorf("get parent team: %w", err)
+		}
+		visited[team.ID] = true
+
+		candidates, err = s.teamCandidates(ctx, team.ID, excluded, explanation)
+		if err != nil {
+			return nil, err
+		}
+		explanation.FallbackTeam = team.Name
+	}
+
+	if len(candidates) == 0 {
+		explanation.FallbackTeam = ""
+	} else if explanation.FallbackTeam != "" {
+		s.logger.Info("reviewer candidates taken from parent team",
+			zap.Int("team_id", teamID),
+			zap.String("fallback_team", explanation.FallbackTeam),
+			zap.Int("count", len(candidates)),
+		)
+	}
+
+	return candidates, nil
+}
+
+// teamCandidates отбирает кандидатов из участников одной команды. Размер команды и число кандидатов
+// записываются в объяснение, исключённые участники добавляются к уже записанным
+func (s *PRService) teamCandidates(
+	ctx context.Context,
+	teamID int,
+	excluded map[string]domain.ExclusionReason,
+	explanation *domain.AssignmentExplanation,
+) ([]*domain.User, error) {
+	pool, err := s.userRepo.GetAssignmentPool(ctx, teamID)
+	if err != nil {
+		s.logger.Error("failed to get reviewer candidates",
+			zap.Int("team_id", teamID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("get candidates: %w", err)
+	}
+
+	candidates, exclusions := domain.FilterPool(pool, excluded)
+	explanation.PoolSize = len(pool)
+	explanation.Candidates = len(candidates)
+
+	// Участник нескольких команд исключается один раз
+	seen := make(map[string]bool, len(explanation.Excluded))
+	for _, e := range explanation.Excluded {
+		seen[e.UserID] = true
+	}
+	for _, e := range exclusions {
+		if !seen[e.UserID] {
+			explanation.Excluded = append(explanation.Excluded, e)
+		}
+	}
+
+	s.logger.Debug("reviewers candidates found",
+		zap.Int("team_id", teamID),
+		zap.Int("pool_size", len(pool)),
+		zap.Int("count", len(candidates)),
+	)
+
+	return candidates, nil
+}
+
+// exclusionReasons сопоставляет пользователям одну причину исключения
+func exclusionReasons(userIDs []string, reason domain.ExclusionReason) map[string]domain.ExclusionReason {
+	reasons := make(map[string]domain.ExclusionReason, len(userIDs))
+	for _, id := range userIDs {
+		reasons[id] = reason
+	}
+	return reasons
+}
+
+// noCandidateError поясняет, почему нет кандидатов: если кто-то исключён
+// из-за лимита открытых ревью, об этом сообщается в ошибке
+func noCandidateError(explanation *domain.AssignmentExplanation) error {
+	if saturated := domain.CountExclusions(explanation.Excluded, domain.ExclusionAtCapacity); saturated > 0 {
+		return fmt.Errorf("%w: %d candidate(s) are at their open review limit", pkgErrors.ErrNoCandidate, saturated)
+	}
+
+	return pkgErrors.ErrNoCandidate
 }
 
 // contains проверяет наличие элемента в срезе
@@ -284,11 +1186,3 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
-
-func (s *PRService) selectRandomReviewer(candidates []*domain.User) string {
-	if len(candidates) == 0 {
-		return ""
-	}
-	idx := s.rng.Intn(len(candidates))
-	return candidates[idx].ID
-}
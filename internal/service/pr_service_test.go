@@ -0,0 +1,839 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+	pkgErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+func newRankingService(mode domain.AssignmentMode, seed int64) *PRService {
+	return &PRService{
+		mode:   mode,
+		logger: zap.NewNop(),
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+func rankedIDs(users []*domain.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+// offHoursSchedule - расписание, рабочее время по которому начнётся примерно через два часа
+func offHoursSchedule(now time.Time) *domain.WorkSchedule {
+	return &domain.WorkSchedule{
+		Timezone: "UTC",
+		Start:    now.UTC().Add(2 * time.Hour).Format("15:04"),
+		End:      now.UTC().Add(3 * time.Hour).Format("15:04"),
+		Days:     []int{1, 2, 3, 4, 5, 6, 7},
+	}
+}
+
+func TestRankCandidates_SameSeedSameOrder(t *testing.T) {
+	candidates := []*domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}, {ID: "u4"}}
+
+	first := newRankingService(domain.AssignmentModeRandom, 42).rankCandidates(candidates, nil)
+	second := newRankingService(domain.AssignmentModeRandom, 42).rankCandidates(candidates, nil)
+
+	assert.Equal(t, rankedIDs(first), rankedIDs(second))
+	assert.ElementsMatch(t, []string{"u1", "u2", "u3", "u4"}, rankedIDs(first))
+}
+
+func TestRankCandidates_RecentReviewersRankLast(t *testing.T) {
+	candidates := []*domain.User{
+		{ID: "u1", ReviewWeight: domain.MaxReviewWeight}, // большой вес не поднимает недавнего ревьюера
+		{ID: "u2"},
+		{ID: "u3"},
+		{ID: "u4"},
+	}
+	recent := map[string]int{"u1": 2, "u2": 1}
+
+	for seed := int64(0); seed < 50; seed++ {
+		ranked := rankedIDs(newRankingService(domain.AssignmentModeRandom, seed).rankCandidates(candidates, recent))
+
+		require.Len(t, ranked, 4)
+		assert.ElementsMatch(t, []string{"u3", "u4"}, ranked[:2], "seed %d", seed)
+		assert.Equal(t, []string{"u2", "u1"}, ranked[2:], "seed %d", seed)
+	}
+}
+
+func TestRankCandidates_WeightsWithoutRecent(t *testing.T) {
+	candidates := []*domain.User{{ID: "heavy", ReviewWeight: 3}, {ID: "light", ReviewWeight: 1}}
+	s := newRankingService(domain.AssignmentModeRandom, 1)
+
+	const runs = 4000
+	heavyFirst := 0
+	for i := 0; i < runs; i++ {
+		if s.rankCandidates(candidates, nil)[0].ID == "heavy" {
+			heavyFirst++
+		}
+	}
+
+	// Кандидат с весом 3 первый с вероятностью 3/4
+	assert.InDelta(t, 0.75, float64(heavyFirst)/runs, 0.03)
+}
+
+func TestRankCandidates_WorkingHoursBeforeRotation(t *testing.T) {
+	now := time.Now()
+	candidates := []*domain.User{
+		{ID: "off-hours", Schedule: offHoursSchedule(now)},
+		{ID: "recent"},
+		{ID: "fresh"},
+	}
+	recent := map[string]int{"recent": 3}
+
+	for seed := int64(0); seed < 20; seed++ {
+		ranked := rankedIDs(newRankingService(domain.AssignmentModeWorkingHours, seed).rankCandidates(candidates, recent))
+
+		// Рабочее время важнее ротации: недавний ревьюер, который сейчас работает,
+		// идёт раньше того, у кого рабочий день ещё не начался. Среди работающих порядок ротации сохраняется
+		assert.Equal(t, []string{"fresh", "recent", "off-hours"}, ranked, "seed %d", seed)
+	}
+
+	// В режиме random расписание не учитывается, и недавний ревьюер остаётся последним
+	ranked := rankedIDs(newRankingService(domain.AssignmentModeRandom, 7).rankCandidates(candidates, recent))
+	assert.Equal(t, "recent", ranked[2])
+}
+
+// recentReviewsRepo отвечает только на CountRecentReviews и запоминает запрошенное окно
+type recentReviewsRepo struct {
+	repository.PullRequestRepository
+	counts map[string]int
+	calls  int
+	since  time.Time
+}
+
+func (r *recentReviewsRepo) CountRecentReviews(_ context.Context, _ string, since time.Time) (map[string]int, error) {
+	r.calls++
+	r.since = since
+	return r.counts, nil
+}
+
+func TestRecentReviews_RotationWindow(t *testing.T) {
+	repo := &recentReviewsRepo{counts: map[string]int{"u2": 1}}
+	s := &PRService{prRepo: repo, logger: zap.NewNop()}
+
+	// Ротация отключена - история ревью не запрашивается
+	recent, err := s.recentReviews(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Nil(t, recent)
+	assert.Zero(t, repo.calls)
+
+	s.rotationWindow = 24 * time.Hour
+	recent, err = s.recentReviews(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"u2": 1}, recent)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), repo.since, time.Minute)
+}
+
+// addReviewerRepo хранит один PR и запоминает добавленных ревьюеров и замены
+type addReviewerRepo struct {
+	repository.PullRequestRepository
+	pr       *domain.PullRequest
+	added    []string
+	replaced []string
+}
+
+func (r *addReviewerRepo) GetByID(context.Context, string) (*domain.PullRequest, error) {
+	return r.pr, nil
+}
+
+func (r *addReviewerRepo) AddReviewer(_ context.Context, _, userID, _ string, _ int) error {
+	r.added = append(r.added, userID)
+	return nil
+}
+
+func (r *addReviewerRepo) ReplaceReviewer(_ context.Context, _, _, newUserID, _ string, _ int) error {
+	r.replaced = append(r.replaced, newUserID)
+	return nil
+}
+
+// activeUserRepo возвращает активного участника команды backend
+type activeUserRepo struct {
+	repository.UserRepository
+}
+
+func (activeUserRepo) GetByID(_ context.Context, id string) (*domain.User, error) {
+	return &domain.User{ID: id, IsActive: true, Teams: []string{"backend"}}, nil
+}
+
+func TestAddReviewer_RespectsReviewerCap(t *testing.T) {
+	prRepo := &addReviewerRepo{pr: &domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "u1",
+		TeamName:          "backend",
+		Status:            domain.StatusOpen,
+		AssignedReviewers: []string{"u2", "u3"},
+		Version:           1,
+	}}
+	s := &PRService{prRepo: prRepo, userRepo: activeUserRepo{}, logger: zap.NewNop()}
+
+	_, err := s.AddReviewer(context.Background(), "pr-1", "u4", "u1", 0)
+	assert.ErrorIs(t, err, pkgErrors.ErrTooManyReviewers)
+	assert.Empty(t, prRepo.added)
+
+	prRepo.pr.AssignedReviewers = []string{"u2"}
+	_, err = s.AddReviewer(context.Background(), "pr-1", "u4", "u1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u4"}, prRepo.added)
+}
+
+// chatOnlyNotifier, как канал чата, принимает события только команд с вебхуком
+type chatOnlyNotifier struct {
+	mu     sync.Mutex
+	events []*domain.PREvent
+}
+
+func (n *chatOnlyNotifier) Wants(_ domain.PREventKind, team *domain.Team) bool {
+	return team != nil && team.ChatWebhookURL != ""
+}
+
+func (n *chatOnlyNotifier) NotifyPREvent(_ context.Context, event *domain.PREvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+	return nil
+}
+
+// eventTeamRepo возвращает команду с заданным вебхуком и считает чтения
+type eventTeamRepo struct {
+	repository.TeamRepository
+	webhookURL string
+	reads      atomic.Int32
+}
+
+func (r *eventTeamRepo) GetByID(_ context.Context, id int) (*domain.Team, error) {
+	r.reads.Add(1)
+	return &domain.Team{ID: id, Name: "backend", ChatWebhookURL: r.webhookURL}, nil
+}
+
+// eventUserRepo считает чтения пользователей
+type eventUserRepo struct {
+	repository.UserRepository
+	reads atomic.Int32
+}
+
+func (r *eventUserRepo) GetByID(_ context.Context, id string) (*domain.User, error) {
+	r.reads.Add(1)
+	return &domain.User{ID: id, Username: id}, nil
+}
+
+func TestNotifyPREvent_BuildsEventOnlyWhenWanted(t *testing.T) {
+	pr := &domain.PullRequest{ID: "pr-1", AuthorID: "u1", TeamID: 7, Status: domain.StatusOpen}
+	teamRepo := &eventTeamRepo{}
+	userRepo := &eventUserRepo{}
+	notifier := &chatOnlyNotifier{}
+	s := &PRService{
+		prRepo:   &addReviewerRepo{pr: pr},
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		notifier: notifier,
+		logger:   zap.NewNop(),
+	}
+
+	// PR без команды - событие никому не нужно, данные не читаются
+	s.notifyPREvent(context.Background(), domain.PREventAssigned, &domain.PullRequest{ID: "pr-2"}, []string{"u2"}, "")
+	s.Wait()
+	assert.Zero(t, teamRepo.reads.Load())
+
+	// У команды нет вебхука - читается только команда
+	s.notifyPREvent(context.Background(), domain.PREventAssigned, pr, []string{"u2", "u3"}, "")
+	s.Wait()
+	assert.EqualValues(t, 1, teamRepo.reads.Load())
+	assert.Zero(t, userRepo.reads.Load())
+	assert.Empty(t, notifier.events)
+
+	// Вебхук задан - событие собирается и отправляется; Wait дожидается отправки
+	teamRepo.webhookURL = "https://chat.example.com/hooks/1"
+	s.notifyPREvent(context.Background(), domain.PREventAssigned, pr, []string{"u2", "u3"}, "")
+	s.Wait()
+	assert.EqualValues(t, 3, userRepo.reads.Load()) // автор и два ревьюера
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, "backend", notifier.events[0].Team.Name)
+	assert.Len(t, notifier.events[0].Reviewers, 2)
+}
+
+// mergeRepo возвращает открытый PR, а UpdateStatus сообщает, сменил ли запрос статус
+type mergeRepo struct {
+	repository.PullRequestRepository
+	pr      *domain.PullRequest
+	changed bool
+}
+
+func (r *mergeRepo) GetByID(context.Context, string) (*domain.PullRequest, error) {
+	return r.pr, nil
+}
+
+func (r *mergeRepo) UpdateStatus(context.Context, string, string, *time.Time, int) (bool, error) {
+	return r.changed, nil
+}
+
+func TestMergePR_NotifiesOnlyOnTransition(t *testing.T) {
+	prRepo := &mergeRepo{pr: &domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "u1",
+		TeamID:            7,
+		Status:            domain.StatusOpen,
+		AssignedReviewers: []string{"u2"},
+		Version:           1,
+	}}
+	notifier := &chatOnlyNotifier{}
+	s := &PRService{
+		prRepo:   prRepo,
+		userRepo: &eventUserRepo{},
+		teamRepo: &eventTeamRepo{webhookURL: "https://chat.example.com/hooks/1"},
+		notifier: notifier,
+		logger:   zap.NewNop(),
+	}
+
+	// Параллельный запрос смержил PR раньше - повторного уведомления нет
+	_, err := s.MergePR(context.Background(), "pr-1", 0)
+	require.NoError(t, err)
+	s.Wait()
+	assert.Empty(t, notifier.events)
+
+	prRepo.changed = true
+	_, err = s.MergePR(context.Background(), "pr-1", 0)
+	require.NoError(t, err)
+	s.Wait()
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, domain.PREventMerged, notifier.events[0].Kind)
+}
+
+func TestMergePR_AlreadyMergedChecksVersion(t *testing.T) {
+	mergedAt := time.Now()
+	prRepo := &mergeRepo{pr: &domain.PullRequest{
+		ID:       "pr-1",
+		AuthorID: "u1",
+		Status:   domain.StatusMerged,
+		MergedAt: &mergedAt,
+		Version:  3,
+	}}
+	s := &PRService{prRepo: prRepo, logger: zap.NewNop()}
+
+	// Клиент видел PR до мержа - его версия устарела
+	_, err := s.MergePR(context.Background(), "pr-1", 2)
+	assert.ErrorIs(t, err, pkgErrors.ErrVersionConflict)
+
+	pr, err := s.MergePR(context.Background(), "pr-1", 3)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusMerged, pr.Status)
+
+	_, err = s.MergePR(context.Background(), "pr-1", 0)
+	require.NoError(t, err)
+}
+
+// poolUserRepo хранит пользователей, их команды и участников команд для подбора ревьюеров
+type poolUserRepo struct {
+	repository.UserRepository
+	users map[string]*domain.User
+	teams map[string][]*domain.Team // команды пользователя
+	pools map[int][]*domain.PoolMember
+}
+
+func (r *poolUserRepo) GetByID(_ context.Context, id string) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *poolUserRepo) GetTeams(_ context.Context, userID string) ([]*domain.Team, error) {
+	return r.teams[userID], nil
+}
+
+func (r *poolUserRepo) GetAssignmentPool(_ context.Context, teamID int) ([]*domain.PoolMember, error) {
+	return r.pools[teamID], nil
+}
+
+// hierarchyTeamRepo хранит команды с их родителями
+type hierarchyTeamRepo struct {
+	repository.TeamRepository
+	teams map[int]*domain.Team
+}
+
+func (r *hierarchyTeamRepo) GetByID(_ context.Context, id int) (*domain.Team, error) {
+	team, ok := r.teams[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return team, nil
+}
+
+func poolMember(id string) *domain.PoolMember {
+	return &domain.PoolMember{User: &domain.User{ID: id, IsActive: true}}
+}
+
+func TestCandidatePool_FallsBackToParentTeam(t *testing.T) {
+	// org -> platform -> backend; в backend подходящих кандидатов нет, в platform нет участников
+	userRepo := &poolUserRepo{pools: map[int][]*domain.PoolMember{
+		3: {poolMember("author"), {User: &domain.User{ID: "u2", IsActive: true}, IsAbsent: true}},
+		1: {poolMember("author"), poolMember("u5")},
+	}}
+	s := &PRService{
+		userRepo: userRepo,
+		teamRepo: &hierarchyTeamRepo{teams: map[int]*domain.Team{
+			1: {ID: 1, Name: "org"},
+			2: {ID: 2, Name: "platform", ParentID: 1},
+			3: {ID: 3, Name: "backend", ParentID: 2},
+		}},
+		logger: zap.NewNop(),
+	}
+	excluded := map[string]domain.ExclusionReason{"author": domain.ExclusionAuthor}
+
+	explanation := &domain.AssignmentExplanation{Excluded: []*domain.Exclusion{}}
+	candidates, err := s.candidatePool(context.Background(), 3, excluded, explanation)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u5"}, rankedIDs(candidates))
+	assert.Equal(t, "org", explanation.FallbackTeam)
+	assert.Equal(t, 2, explanation.PoolSize)
+	assert.Equal(t, 1, explanation.Candidates)
+	assert.Equal(t, []*domain.Exclusion{
+		{UserID: "author", Reason: domain.ExclusionAuthor},
+		{UserID: "u2", Reason: domain.ExclusionAbsent},
+	}, explanation.Excluded)
+
+	// В команде есть кандидат - родительская команда не используется
+	userRepo.pools[3] = append(userRepo.pools[3], poolMember("u3"))
+	explanation = &domain.AssignmentExplanation{Excluded: []*domain.Exclusion{}}
+	candidates, err = s.candidatePool(context.Background(), 3, excluded, explanation)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3"}, rankedIDs(candidates))
+	assert.Empty(t, explanation.FallbackTeam)
+
+	// Подходящих нет во всей иерархии
+	delete(userRepo.pools, 1)
+	userRepo.pools[3] = userRepo.pools[3][:2]
+	explanation = &domain.AssignmentExplanation{Excluded: []*domain.Exclusion{}}
+	candidates, err = s.candidatePool(context.Background(), 3, excluded, explanation)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+	assert.Empty(t, explanation.FallbackTeam)
+}
+
+// rulesRepo отдаёт правила назначения автора
+type rulesRepo struct {
+	repository.ReviewerRuleRepository
+	rules []*domain.ReviewerRule
+}
+
+func (r *rulesRepo) ListByAuthor(_ context.Context, _ int, authorID string) ([]*domain.ReviewerRule, error) {
+	var rules []*domain.ReviewerRule
+	for _, rule := range r.rules {
+		if rule.AuthorID == authorID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// createRepo сохраняет созданные PR; errs - ошибки, которые вернут первые вызовы Create
+type createRepo struct {
+	repository.PullRequestRepository
+	errs    []error
+	created []*domain.PullRequest
+}
+
+func (r *createRepo) Create(_ context.Context, pr *domain.PullRequest, _ []string) error {
+	if len(r.errs) > 0 {
+		err := r.errs[0]
+		r.errs = r.errs[1:]
+		return err
+	}
+	r.created = append(r.created, pr)
+	return nil
+}
+
+// newAssignmentService возвращает сервис с автором u1 в команде backend (ID 1) из участников members
+func newAssignmentService(prRepo repository.PullRequestRepository, members ...*domain.PoolMember) (*PRService, *poolUserRepo) {
+	backend := &domain.Team{ID: 1, Name: "backend"}
+	userRepo := &poolUserRepo{
+		users: map[string]*domain.User{"u1": {ID: "u1", IsActive: true, TeamID: 1, Teams: []string{"backend"}}},
+		teams: map[string][]*domain.Team{"u1": {backend}},
+		pools: map[int][]*domain.PoolMember{1: append([]*domain.PoolMember{poolMember("u1")}, members...)},
+	}
+	for _, m := range members {
+		m.User.Teams = []string{"backend"}
+		userRepo.users[m.User.ID] = m.User
+	}
+
+	s := &PRService{
+		prRepo:   prRepo,
+		userRepo: userRepo,
+		teamRepo: &hierarchyTeamRepo{teams: map[int]*domain.Team{1: backend}},
+		ruleRepo: &rulesRepo{},
+		notifier: &chatOnlyNotifier{},
+		mode:     domain.AssignmentModeRandom,
+		logger:   zap.NewNop(),
+		rng:      rand.New(rand.NewSource(1)),
+	}
+	return s, userRepo
+}
+
+func TestCreatePR_ReportsSaturatedTeam(t *testing.T) {
+	limit := 1
+	prRepo := &createRepo{}
+	s, _ := newAssignmentService(prRepo,
+		&domain.PoolMember{User: &domain.User{ID: "u2", IsActive: true, MaxOpenReviews: &limit}, OpenReviews: 1},
+		&domain.PoolMember{User: &domain.User{ID: "u3", IsActive: true, MaxOpenReviews: &limit}, OpenReviews: 3},
+	)
+
+	pr, explanation, err := s.CreatePR(context.Background(), "pr-1", "Add search", "u1", "")
+	require.NoError(t, err)
+	s.Wait()
+
+	assert.Empty(t, pr.AssignedReviewers)
+	assert.Equal(t, domain.ShortfallAtCapacity, explanation.Shortfall)
+	assert.Equal(t, 2, domain.CountExclusions(explanation.Excluded, domain.ExclusionAtCapacity))
+}
+
+func TestCreatePR_RetriesWhenReviewerTakenConcurrently(t *testing.T) {
+	prRepo := &createRepo{errs: []error{fmt.Errorf("%w: u2", repository.ErrAtCapacity)}}
+	s, _ := newAssignmentService(prRepo, poolMember("u2"), poolMember("u3"))
+
+	pr, explanation, err := s.CreatePR(context.Background(), "pr-1", "Add search", "u1", "")
+	require.NoError(t, err)
+	s.Wait()
+
+	require.Len(t, prRepo.created, 1)
+	assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+	assert.Empty(t, explanation.Shortfall)
+
+	// Ревьюеров занимают при каждой попытке - запрос завершается ошибкой
+	capacityErr := fmt.Errorf("%w: u2", repository.ErrAtCapacity)
+	prRepo.errs = []error{capacityErr, capacityErr, capacityErr}
+	_, _, err = s.CreatePR(context.Background(), "pr-2", "Add search", "u1", "")
+	assert.ErrorIs(t, err, pkgErrors.ErrReviewerAtCapacity)
+	assert.Len(t, prRepo.created, 1)
+}
+
+func TestManualReviewer_ForbiddenByTeamRule(t *testing.T) {
+	prRepo := &addReviewerRepo{pr: &domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "u1",
+		TeamID:            1,
+		TeamName:          "backend",
+		Status:            domain.StatusOpen,
+		AssignedReviewers: []string{"u2"},
+		Version:           1,
+	}}
+	s, _ := newAssignmentService(prRepo, poolMember("u2"), poolMember("u3"), poolMember("u4"))
+	s.ruleRepo = &rulesRepo{rules: []*domain.ReviewerRule{
+		{TeamID: 1, AuthorID: "u1", ReviewerID: "u3", Kind: domain.ReviewerRuleForbid},
+		{TeamID: 1, AuthorID: "u2", ReviewerID: "u4", Kind: domain.ReviewerRuleForbid}, // правило другого автора
+	}}
+
+	_, err := s.AddReviewer(context.Background(), "pr-1", "u3", "u1", 0)
+	assert.ErrorIs(t, err, pkgErrors.ErrReviewerForbidden)
+
+	_, _, _, err = s.ReassignReviewer(context.Background(), "pr-1", "u2", "u3", 0)
+	assert.ErrorIs(t, err, pkgErrors.ErrReviewerForbidden)
+
+	assert.Empty(t, prRepo.added)
+	assert.Empty(t, prRepo.replaced)
+
+	_, err = s.AddReviewer(context.Background(), "pr-1", "u4", "u1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u4"}, prRepo.added)
+}
+
+func TestReassignReviewer_ExplicitKeepsSeniorRule(t *testing.T) {
+	prRepo := &addReviewerRepo{pr: &domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "u1",
+		TeamID:            1,
+		TeamName:          "backend",
+		Status:            domain.StatusOpen,
+		AssignedReviewers: []string{"u2", "u3"},
+		Version:           1,
+	}}
+	senior := func(id string) *domain.PoolMember {
+		m := poolMember(id)
+		m.User.Seniority = domain.SenioritySenior
+		return m
+	}
+	s, _ := newAssignmentService(prRepo, senior("u2"), poolMember("u3"), poolMember("u4"), senior("u5"))
+	s.teamRepo = &hierarchyTeamRepo{teams: map[int]*domain.Team{1: {ID: 1, Name: "backend", MinSeniorReviewers: 1}}}
+
+	// Единственного senior нельзя заменить на не-senior
+	_, _, _, err := s.ReassignReviewer(context.Background(), "pr-1", "u2", "u4", 0)
+	assert.ErrorIs(t, err, pkgErrors.ErrSeniorReviewerRequired)
+	assert.Empty(t, prRepo.replaced)
+
+	_, _, _, err = s.ReassignReviewer(context.Background(), "pr-1", "u2", "u5", 0)
+	require.NoError(t, err)
+	_, _, _, err = s.ReassignReviewer(context.Background(), "pr-1", "u3", "u4", 0)
+	require.NoError(t, err)
+	s.Wait()
+	assert.Equal(t, []string{"u5", "u4"}, prRepo.replaced)
+}
+
+func TestReassignReviewer_ExplicitValidation(t *testing.T) {
+	limit := 2
+	members := []*domain.PoolMember{
+		{User: &domain.User{ID: "u2", IsActive: true, Seniority: domain.SenioritySenior}},
+		{User: &domain.User{ID: "absent", IsActive: true}, IsAbsent: true},
+		{User: &domain.User{ID: "busy", IsActive: true, MaxOpenReviews: &limit}, OpenReviews: 2},
+		{User: &domain.User{ID: "forbidden", IsActive: true, Seniority: domain.SenioritySenior}},
+		{User: &domain.User{ID: "middle", IsActive: true, Seniority: domain.SeniorityMiddle}},
+	}
+
+	tests := []struct {
+		name  string
+		newID string
+		want  error
+	}{
+		{name: "absent", newID: "absent", want: pkgErrors.ErrReviewerAbsent},
+		{name: "at capacity", newID: "busy", want: pkgErrors.ErrReviewerAtCapacity},
+		{name: "forbidden", newID: "forbidden", want: pkgErrors.ErrReviewerForbidden},
+		{name: "non-senior replacing senior", newID: "middle", want: pkgErrors.ErrSeniorReviewerRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prRepo := &addReviewerRepo{pr: &domain.PullRequest{
+				ID:                "pr-1",
+				AuthorID:          "u1",
+				TeamID:            1,
+				TeamName:          "backend",
+				Status:            domain.StatusOpen,
+				AssignedReviewers: []string{"u2"},
+				Version:           1,
+			}}
+			s, _ := newAssignmentService(prRepo, members...)
+			s.teamRepo = &hierarchyTeamRepo{teams: map[int]*domain.Team{1: {ID: 1, Name: "backend", MinSeniorReviewers: 1}}}
+			s.ruleRepo = &rulesRepo{rules: []*domain.ReviewerRule{
+				{TeamID: 1, AuthorID: "u1", ReviewerID: "forbidden", Kind: domain.ReviewerRuleForbid},
+			}}
+
+			_, _, _, err := s.ReassignReviewer(context.Background(), "pr-1", "u2", tt.newID, 0)
+			assert.ErrorIs(t, err, tt.want)
+			assert.Empty(t, prRepo.replaced)
+		})
+	}
+}
+
+func TestAssignment_SkipsAbsentReviewers(t *testing.T) {
+	absent := func(id string) *domain.PoolMember {
+		m := poolMember(id)
+		m.IsAbsent = true
+		return m
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		prRepo := &createRepo{}
+		s, _ := newAssignmentService(prRepo, absent("u2"), poolMember("u3"), absent("u4"), poolMember("u5"))
+		s.rng = rand.New(rand.NewSource(seed))
+
+		pr, explanation, err := s.CreatePR(context.Background(), "pr-1", "Add search", "u1", "")
+		require.NoError(t, err)
+		s.Wait()
+
+		assert.ElementsMatch(t, []string{"u3", "u5"}, pr.AssignedReviewers, "seed %d", seed)
+		assert.Equal(t, 2, domain.CountExclusions(explanation.Excluded, domain.ExclusionAbsent), "seed %d", seed)
+	}
+
+	// Автоматическая замена тоже не выбирает отсутствующих
+	prRepo := &addReviewerRepo{pr: &domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "u1",
+		TeamID:            1,
+		TeamName:          "backend",
+		Status:            domain.StatusOpen,
+		AssignedReviewers: []string{"u3"},
+		Version:           1,
+	}}
+	s, _ := newAssignmentService(prRepo, absent("u2"), poolMember("u3"), absent("u4"), poolMember("u5"))
+
+	newID, _, explanation, err := s.ReassignReviewer(context.Background(), "pr-1", "u3", "", 0)
+	require.NoError(t, err)
+	s.Wait()
+	assert.Equal(t, "u5", newID)
+	assert.Equal(t, 2, domain.CountExclusions(explanation.Excluded, domain.ExclusionAbsent))
+}
+
+func TestCreatePR_PrefersReviewersInWorkingHours(t *testing.T) {
+	offHours := func(id string) *domain.PoolMember {
+		m := poolMember(id)
+		m.User.Schedule = offHoursSchedule(time.Now())
+		return m
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		prRepo := &createRepo{}
+		s, _ := newAssignmentService(prRepo, offHours("u2"), poolMember("u3"), offHours("u4"), poolMember("u5"))
+		s.mode = domain.AssignmentModeWorkingHours
+		s.rng = rand.New(rand.NewSource(seed))
+
+		pr, explanation, err := s.CreatePR(context.Background(), "pr-1", "Add search", "u1", "")
+		require.NoError(t, err)
+		s.Wait()
+
+		// Участники вне рабочего времени не вытесняют тех, у кого оно идёт сейчас
+		assert.ElementsMatch(t, []string{"u3", "u5"}, pr.AssignedReviewers, "seed %d", seed)
+		assert.Equal(t, domain.AssignmentModeWorkingHours, explanation.Strategy)
+		for _, choice := range explanation.Selected {
+			assert.Equal(t, domain.SelectionWorkingHours, choice.Reason)
+		}
+	}
+
+	// Если работающих не хватает, назначается и участник вне рабочего времени
+	prRepo := &createRepo{}
+	s, _ := newAssignmentService(prRepo, offHours("u2"), poolMember("u3"))
+	s.mode = domain.AssignmentModeWorkingHours
+
+	pr, _, err := s.CreatePR(context.Background(), "pr-1", "Add search", "u1", "")
+	require.NoError(t, err)
+	s.Wait()
+	assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+}
+
+func TestReassignReviewer_WeightedSelection(t *testing.T) {
+	prRepo := &addReviewerRepo{pr: &domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "u1",
+		TeamID:            1,
+		TeamName:          "backend",
+		Status:            domain.StatusOpen,
+		AssignedReviewers: []string{"u2"},
+		Version:           1,
+	}}
+	s, _ := newAssignmentService(prRepo,
+		poolMember("u2"),
+		&domain.PoolMember{User: &domain.User{ID: "heavy", IsActive: true, ReviewWeight: 3}},
+		&domain.PoolMember{User: &domain.User{ID: "light", IsActive: true}},
+	)
+
+	const runs = 2000
+	heavy := 0
+	for i := 0; i < runs; i++ {
+		newID, _, explanation, err := s.ReassignReviewer(context.Background(), "pr-1", "u2", "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, domain.SelectionRandom, explanation.Selected[0].Reason)
+		if newID == "heavy" {
+			heavy++
+		}
+	}
+	s.Wait()
+
+	// Участник с весом 3 выбирается с вероятностью 3/4
+	assert.InDelta(t, 0.75, float64(heavy)/runs, 0.04)
+}
+
+func TestAssignmentExplanation_CreateAndReassign(t *testing.T) {
+	prRepo := &createRepo{}
+	s, userRepo := newAssignmentService(prRepo,
+		poolMember("u2"),
+		&domain.PoolMember{User: &domain.User{ID: "u3", IsActive: true}, IsAbsent: true},
+		poolMember("u4"),
+		poolMember("u5"),
+		&domain.PoolMember{User: &domain.User{ID: "u6"}},
+	)
+	s.ruleRepo = &rulesRepo{rules: []*domain.ReviewerRule{
+		{TeamID: 1, AuthorID: "u1", ReviewerID: "u2", Kind: domain.ReviewerRuleForbid},
+		{TeamID: 1, AuthorID: "u1", ReviewerID: "u4", Kind: domain.ReviewerRulePrefer},
+	}}
+
+	pr, explanation, err := s.CreatePR(context.Background(), "pr-1", "Add search", "u1", "")
+	require.NoError(t, err)
+	s.Wait()
+
+	assert.Equal(t, []string{"u4", "u5"}, pr.AssignedReviewers)
+	assert.Equal(t, &domain.AssignmentExplanation{
+		TeamName:   "backend",
+		Strategy:   domain.AssignmentModeRandom,
+		PoolSize:   6,
+		Candidates: 2,
+		Excluded: []*domain.Exclusion{
+			{UserID: "u1", Reason: domain.ExclusionAuthor},
+			{UserID: "u2", Reason: domain.ExclusionForbidden},
+			{UserID: "u3", Reason: domain.ExclusionAbsent},
+			{UserID: "u6", Reason: domain.ExclusionInactive},
+		},
+		Selected: []*domain.ReviewerChoice{
+			{UserID: "u4", Reason: domain.SelectionPreferRule},
+			{UserID: "u5", Reason: domain.SelectionRandom},
+		},
+	}, explanation)
+
+	// Замена: уже назначенные ревьюеры исключаются вместе с остальными
+	u7 := poolMember("u7")
+	u7.User.Teams = []string{"backend"}
+	userRepo.users["u7"] = u7.User
+	userRepo.pools[1] = append(userRepo.pools[1], u7)
+	s.prRepo = &addReviewerRepo{pr: pr}
+
+	newID, _, explanation, err := s.ReassignReviewer(context.Background(), "pr-1", "u5", "", 0)
+	require.NoError(t, err)
+	s.Wait()
+
+	assert.Equal(t, "u7", newID)
+	assert.Equal(t, &domain.AssignmentExplanation{
+		TeamName:   "backend",
+		Strategy:   domain.AssignmentModeRandom,
+		PoolSize:   7,
+		Candidates: 1,
+		Excluded: []*domain.Exclusion{
+			{UserID: "u1", Reason: domain.ExclusionAuthor},
+			{UserID: "u2", Reason: domain.ExclusionForbidden},
+			{UserID: "u3", Reason: domain.ExclusionAbsent},
+			{UserID: "u4", Reason: domain.ExclusionAlreadyAssigned},
+			{UserID: "u5", Reason: domain.ExclusionAlreadyAssigned},
+			{UserID: "u6", Reason: domain.ExclusionInactive},
+		},
+		Selected: []*domain.ReviewerChoice{{UserID: "u7", Reason: domain.SelectionRandom}},
+	}, explanation)
+
+	// Замена на указанного коллегу объясняется причиной requested
+	_, _, explanation, err = s.ReassignReviewer(context.Background(), "pr-1", "u5", "u7", 0)
+	require.NoError(t, err)
+	s.Wait()
+	assert.Equal(t, []*domain.ReviewerChoice{{UserID: "u7", Reason: domain.SelectionRequested}}, explanation.Selected)
+}
+
+func TestPreviewPR_MatchesCreatePR(t *testing.T) {
+	members := func() []*domain.PoolMember {
+		return []*domain.PoolMember{
+			poolMember("u2"),
+			poolMember("u3"),
+			{User: &domain.User{ID: "u4", IsActive: true}, IsAbsent: true},
+			{User: &domain.User{ID: "u5", IsActive: true, ReviewWeight: 2}},
+			poolMember("u6"),
+		}
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		// Сервисы с одинаковым seed подбирают ревьюеров одинаково
+		previewRepo := &createRepo{}
+		previewer, _ := newAssignmentService(previewRepo, members()...)
+		previewer.rng = rand.New(rand.NewSource(seed))
+		creator, _ := newAssignmentService(&createRepo{}, members()...)
+		creator.rng = rand.New(rand.NewSource(seed))
+
+		preview, err := previewer.PreviewPR(context.Background(), "u1", "")
+		require.NoError(t, err)
+		pr, explanation, err := creator.CreatePR(context.Background(), "pr-1", "Add search", "u1", "")
+		require.NoError(t, err)
+		creator.Wait()
+
+		assert.Equal(t, pr.AssignedReviewers, preview.ProposedReviewers, "seed %d", seed)
+		assert.Equal(t, explanation, preview.Assignment, "seed %d", seed)
+		assert.Len(t, preview.EligiblePool, 4, "seed %d", seed)
+		assert.Empty(t, previewRepo.created, "preview must not create a PR")
+	}
+}
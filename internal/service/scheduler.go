@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+	pkgErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+// Имена фоновых задач
+const (
+	JobReviewSLA          = "review_sla"
+	JobIdempotencyCleanup = "idempotency_cleanup"
+	JobReviewDigest       = "review_digest"
+)
+
+// finishTimeout ограничивает сохранение результата запуска, прерванного остановкой сервиса
+const finishTimeout = 5 * time.Second
+
+// JobFunc выполняет фоновую задачу и возвращает число обработанных объектов
+type JobFunc func(ctx context.Context, now time.Time) (int, error)
+
+// Job - фоновая задача планировщика
+type Job struct {
+	Name        string
+	Description string
+	Interval    time.Duration // 0 - только ручной запуск
+	Run         JobFunc
+}
+
+// Scheduler периодически запускает фоновые задачи. Каждую задачу в один момент выполняет
+// только один экземпляр сервиса: запуск берёт advisory-блокировку задачи в Postgres
+type Scheduler struct {
+	jobRepo repository.JobRepository
+	jobs    []*Job
+	byName  map[string]*Job
+	ctx     context.Context // контекст запусков, отменяется при остановке сервиса
+	wg      sync.WaitGroup
+	logger  *zap.Logger
+}
+
+func NewScheduler(jobRepo repository.JobRepository, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		jobRepo: jobRepo,
+		byName:  make(map[string]*Job),
+		ctx:     context.Background(),
+		logger:  logger,
+	}
+}
+
+// Register добавляет задачу. Вызывается до Start
+func (s *Scheduler) Register(job *Job) {
+	if _, ok := s.byName[job.Name]; ok {
+		panic(fmt.Sprintf("job %q already registered", job.Name))
+	}
+	s.jobs = append(s.jobs, job)
+	s.byName[job.Name] = job
+}
+
+// Start запускает задачи с ненулевым интервалом по расписанию до отмены ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	s.ctx = ctx
+
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(job)
+		}()
+
+		s.logger.Info("job scheduled",
+			zap.String("job_name", job.Name),
+			zap.Duration("interval", job.Interval),
+		)
+	}
+}
+
+// Wait ждёт завершения циклов расписания и выполняющихся запусков после отмены контекста Start
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// Jobs возвращает зарегистрированные задачи с их последними запусками
+func (s *Scheduler) Jobs(ctx context.Context) ([]*domain.JobInfo, error) {
+	lastRuns, err := s.jobRepo.LastRuns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get last job runs: %w", err)
+	}
+
+	jobs := make([]*domain.JobInfo, len(s.jobs))
+	for i, job := range s.jobs {
+		jobs[i] = &domain.JobInfo{
+			Name:            job.Name,
+			Description:     job.Description,
+			IntervalSeconds: int64(job.Interval / time.Second),
+			LastRun:         lastRuns[job.Name],
+		}
+	}
+
+	return jobs, nil
+}
+
+// Runs возвращает последние limit запусков задачи
+func (s *Scheduler) Runs(ctx context.Context, jobName string, limit int) ([]*domain.JobRun, error) {
+	if jobName == "" || limit <= 0 {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+	if _, ok := s.byName[jobName]; !ok {
+		return nil, pkgErrors.ErrNotFound
+	}
+
+	runs, err := s.jobRepo.ListRuns(ctx, jobName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list job runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// Trigger запускает задачу вне расписания. Задача выполняется в фоне,
+// возвращается только что созданный запуск. Если задача уже выполняется, возвращает ErrJobRunning
+func (s *Scheduler) Trigger(ctx context.Context, jobName string) (*domain.JobRun, error) {
+	if jobName == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+	job, ok := s.byName[jobName]
+	if !ok {
+		return nil, pkgErrors.ErrNotFound
+	}
+
+	unlock, locked, err := s.jobRepo.TryLock(ctx, job.Name)
+	if err != nil {
+		return nil, fmt.Errorf("lock job: %w", err)
+	}
+	if !locked {
+		return nil, pkgErrors.ErrJobRunning
+	}
+
+	run, err := s.startRun(ctx, job, domain.JobTriggerManual)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	started := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer unlock()
+		s.execute(job, run)
+	}()
+
+	return &started, nil
+}
+
+// loop запускает задачу каждые job.Interval до отмены контекста
+func (s *Scheduler) loop(job *Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.runScheduled(job)
+		}
+	}
+}
+
+// runScheduled выполняет задачу, если её не выполняет другой экземпляр
+// и она не запускалась в текущем интервале
+func (s *Scheduler) runScheduled(job *Job) {
+	unlock, locked, err := s.jobRepo.TryLock(s.ctx, job.Name)
+	if err != nil {
+		s.logger.Error("failed to lock job", zap.String("job_name", job.Name), zap.Error(err))
+		return
+	}
+	if !locked {
+		s.logger.Debug("job is running on another instance", zap.String("job_name", job.Name))
+		return
+	}
+	defer unlock()
+
+	// Тикеры экземпляров не синхронизированы: без этой проверки задача выполнялась бы
+	// по разу на каждый экземпляр за интервал. Запас в 10% компенсирует дрожание тикера
+	last, err := s.jobRepo.ListRuns(s.ctx, job.Name, 1)
+	if err != nil {
+		s.logger.Error("failed to get last job run", zap.String("job_name", job.Name), zap.Error(err))
+		return
+	}
+	if len(last) > 0 && time.Since(last[0].StartedAt) < job.Interval*9/10 {
+		s.logger.Debug("job already ran in this interval", zap.String("job_name", job.Name))
+		return
+	}
+
+	run, err := s.startRun(s.ctx, job, domain.JobTriggerSchedule)
+	if err != nil {
+		s.logger.Error("failed to start job run", zap.String("job_name", job.Name), zap.Error(err))
+		return
+	}
+
+	s.execute(job, run)
+}
+
+func (s *Scheduler) startRun(ctx context.Context, job *Job, trigger domain.JobTrigger) (*domain.JobRun, error) {
+	run := &domain.JobRun{
+		JobName:   job.Name,
+		Trigger:   trigger,
+		Status:    domain.JobRunRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.jobRepo.StartRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("start job run: %w", err)
+	}
+	return run, nil
+}
+
+// execute выполняет задачу и сохраняет результат запуска. Вызывается под блокировкой задачи
+func (s *Scheduler) execute(job *Job, run *domain.JobRun) {
+	processed, err := s.runJob(job, run.StartedAt)
+	run.Finish(processed, err, time.Now())
+
+	fields := []zap.Field{
+		zap.String("job_name", job.Name),
+		zap.Int64("run_id", run.ID),
+		zap.String("trigger", string(run.Trigger)),
+		zap.Int("processed", processed),
+		zap.Duration("duration", run.FinishedAt.Sub(run.StartedAt)),
+	}
+	if err != nil {
+		s.logger.Error("job failed", append(fields, zap.Error(err))...)
+	} else {
+		s.logger.Info("job finished", fields...)
+	}
+
+	// Результат сохраняется и после отмены контекста при остановке сервиса
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(s.ctx), finishTimeout)
+	defer cancel()
+
+	if err := s.jobRepo.FinishRun(ctx, run); err != nil {
+		s.logger.Error("failed to save job run", zap.String("job_name", job.Name), zap.Error(err))
+	}
+}
+
+// runJob вызывает задачу, превращая панику в ошибку запуска
+func (s *Scheduler) runJob(job *Job, now time.Time) (processed int, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return job.Run(s.ctx, now)
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	pkgErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+// fakeJobRepo хранит запуски и блокировки задач в памяти
+type fakeJobRepo struct {
+	mu     sync.Mutex
+	locked map[string]bool
+	runs   []*domain.JobRun // в порядке создания
+}
+
+func newFakeJobRepo() *fakeJobRepo {
+	return &fakeJobRepo{locked: make(map[string]bool)}
+}
+
+func (r *fakeJobRepo) TryLock(_ context.Context, jobName string) (func(), bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.locked[jobName] {
+		return nil, false, nil
+	}
+	r.locked[jobName] = true
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.locked, jobName)
+	}, true, nil
+}
+
+func (r *fakeJobRepo) StartRun(_ context.Context, run *domain.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run.ID = int64(len(r.runs) + 1)
+	copied := *run
+	r.runs = append(r.runs, &copied)
+	return nil
+}
+
+func (r *fakeJobRepo) FinishRun(_ context.Context, run *domain.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *run
+	r.runs[run.ID-1] = &copied
+	return nil
+}
+
+func (r *fakeJobRepo) LastRuns(context.Context) (map[string]*domain.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := make(map[string]*domain.JobRun)
+	for _, run := range r.runs {
+		last[run.JobName] = run
+	}
+	return last, nil
+}
+
+func (r *fakeJobRepo) ListRuns(_ context.Context, jobName string, limit int) ([]*domain.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var runs []*domain.JobRun
+	for i := len(r.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if r.runs[i].JobName == jobName {
+			copied := *r.runs[i]
+			runs = append(runs, &copied)
+		}
+	}
+	return runs, nil
+}
+
+func (r *fakeJobRepo) isLocked(jobName string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.locked[jobName]
+}
+
+func (r *fakeJobRepo) allRuns() []*domain.JobRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*domain.JobRun(nil), r.runs...)
+}
+
+// countingJob возвращает задачу, считающую свои запуски
+func countingJob(interval time.Duration, calls *atomic.Int32) *Job {
+	return &Job{
+		Name:     "test_job",
+		Interval: interval,
+		Run: func(context.Context, time.Time) (int, error) {
+			calls.Add(1)
+			return 3, nil
+		},
+	}
+}
+
+func TestScheduler_RunScheduledSkipsLockedJob(t *testing.T) {
+	repo := newFakeJobRepo()
+	var calls atomic.Int32
+	job := countingJob(time.Hour, &calls)
+
+	s := NewScheduler(repo, zap.NewNop())
+	s.Register(job)
+
+	// Задачу выполняет другой экземпляр
+	repo.locked[job.Name] = true
+	s.runScheduled(job)
+
+	assert.Zero(t, calls.Load())
+	assert.Empty(t, repo.allRuns())
+}
+
+func TestScheduler_RunScheduledOncePerInterval(t *testing.T) {
+	repo := newFakeJobRepo()
+	var calls atomic.Int32
+	job := countingJob(time.Hour, &calls)
+
+	s := NewScheduler(repo, zap.NewNop())
+	s.Register(job)
+
+	// Другой экземпляр запускал задачу минуту назад - в этом интервале она уже выполнена
+	repo.runs = append(repo.runs, &domain.JobRun{
+		ID:        1,
+		JobName:   job.Name,
+		Trigger:   domain.JobTriggerSchedule,
+		Status:    domain.JobRunSucceeded,
+		StartedAt: time.Now().Add(-time.Minute),
+	})
+	s.runScheduled(job)
+	assert.Zero(t, calls.Load())
+	assert.Len(t, repo.allRuns(), 1)
+
+	// Прошлый запуск старше интервала - задача выполняется
+	repo.runs[0].StartedAt = time.Now().Add(-time.Hour)
+	s.runScheduled(job)
+	assert.EqualValues(t, 1, calls.Load())
+
+	runs := repo.allRuns()
+	require.Len(t, runs, 2)
+	assert.Equal(t, domain.JobTriggerSchedule, runs[1].Trigger)
+	assert.Equal(t, domain.JobRunSucceeded, runs[1].Status)
+	assert.Equal(t, 3, runs[1].Processed)
+	assert.False(t, repo.isLocked(job.Name))
+}
+
+func TestScheduler_PanicFailsRun(t *testing.T) {
+	repo := newFakeJobRepo()
+	job := &Job{
+		Name:     "test_job",
+		Interval: time.Hour,
+		Run: func(context.Context, time.Time) (int, error) {
+			panic("boom")
+		},
+	}
+
+	s := NewScheduler(repo, zap.NewNop())
+	s.Register(job)
+	s.runScheduled(job)
+
+	runs := repo.allRuns()
+	require.Len(t, runs, 1)
+	assert.Equal(t, domain.JobRunFailed, runs[0].Status)
+	assert.Equal(t, "job panicked: boom", runs[0].Error)
+	assert.NotNil(t, runs[0].FinishedAt)
+	assert.False(t, repo.isLocked(job.Name))
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	repo := newFakeJobRepo()
+	release := make(chan struct{})
+	var finished atomic.Bool
+	job := &Job{
+		Name: "test_job",
+		Run: func(context.Context, time.Time) (int, error) {
+			<-release
+			finished.Store(true)
+			return 1, nil
+		},
+	}
+
+	s := NewScheduler(repo, zap.NewNop())
+	s.Register(job)
+
+	_, err := s.Trigger(context.Background(), "")
+	assert.ErrorIs(t, err, pkgErrors.ErrInvalidInput)
+	_, err = s.Trigger(context.Background(), "unknown")
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+
+	run, err := s.Trigger(context.Background(), job.Name)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobTriggerManual, run.Trigger)
+	assert.Equal(t, domain.JobRunRunning, run.Status)
+
+	// Пока запуск выполняется, повторный запуск отклоняется
+	_, err = s.Trigger(context.Background(), job.Name)
+	assert.ErrorIs(t, err, pkgErrors.ErrJobRunning)
+
+	// Wait дожидается ручного запуска
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	s.Wait()
+	assert.True(t, finished.Load())
+
+	runs := repo.allRuns()
+	require.Len(t, runs, 1)
+	assert.Equal(t, domain.JobRunSucceeded, runs[0].Status)
+	assert.Equal(t, 1, runs[0].Processed)
+	assert.False(t, repo.isLocked(job.Name))
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
+	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
+	pkgErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+// SLAActorID - от чьего имени в журнал изменений ревьюеров записываются изменения, сделанные эскалацией
+const SLAActorID = "system:review-sla"
+
+// SLAService находит ревью, не выполненные в срок SLA команды, и эскалирует их
+type SLAService struct {
+	prRepo    repository.PullRequestRepository
+	prService *PRService
+	logger    *zap.Logger
+}
+
+func NewSLAService(prRepo repository.PullRequestRepository, prService *PRService, logger *zap.Logger) *SLAService {
+	return &SLAService{
+		prRepo:    prRepo,
+		prService: prService,
+		logger:    logger,
+	}
+}
+
+// EscalateOverdueReviews эскалирует все просроченные на момент now ревью и возвращает число эскалаций.
+// Ошибка эскалации одного ревью не останавливает обработку остальных: такое ревью будет
+// обработано при следующем запуске
+func (s *SLAService) EscalateOverdueReviews(ctx context.Context, now time.Time) (int, error) {
+	overdue, err := s.prRepo.ListOverdueReviews(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("list overdue reviews: %w", err)
+	}
+
+	escalated := 0
+	for _, review := range overdue {
+		if err := ctx.Err(); err != nil {
+			return escalated, err
+		}
+
+		ok, err := s.escalate(ctx, review)
+		if err != nil {
+			s.logger.Error("failed to escalate overdue review",
+				zap.String("pr_id", review.PullRequestID),
+				zap.String("reviewer_id", review.ReviewerID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if ok {
+			escalated++
+		}
+	}
+
+	return escalated, nil
+}
+
+// escalate заменяет просрочившего ревьюера или добавляет ещё одного согласно SLA команды
+// и сохраняет событие эскалации. Возвращает false, если эскалация больше не нужна
+func (s *SLAService) escalate(ctx context.Context, review *domain.OverdueReview) (bool, error) {
+	pr, err := s.prService.getOpenPR(ctx, review.PullRequestID, 0)
+	if err != nil {
+		if errors.Is(err, pkgErrors.ErrNotFound) || errors.Is(err, pkgErrors.ErrPRMerged) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !contains(pr.AssignedReviewers, review.ReviewerID) {
+		return false, nil
+	}
+
+	// На PR с максимумом ревьюеров добавить ещё одного нельзя, поэтому просрочившего ревьюера заменяют
+	action := review.SLA.Action
+	if action == domain.SLAActionAddReviewer && len(pr.AssignedReviewers) >= domain.MaxReviewersPerPR {
+		action = domain.SLAActionReassign
+	}
+
+	escalation := &domain.ReviewEscalation{
+		PullRequestID: review.PullRequestID,
+		ReviewerID:    review.ReviewerID,
+		Action:        action,
+	}
+
+	// Если кандидатов нет, событие всё равно сохраняется, чтобы о просрочке узнали
+	newReviewerID, _, err := s.prService.selectReplacement(ctx, pr, review.ReviewerID)
+	switch {
+	case errors.Is(err, pkgErrors.ErrNoCandidate):
+		s.logger.Warn("no candidate to escalate overdue review",
+			zap.String("pr_id", pr.ID),
+			zap.String("reviewer_id", review.ReviewerID),
+			zap.Error(err),
+		)
+	case err != nil:
+		return false, err
+	default:
+		if err := s.applyEscalation(ctx, pr, review.ReviewerID, action, newReviewerID); err != nil {
+			return false, err
+		}
+		escalation.NewReviewerID = newReviewerID
+
+		if action == domain.SLAActionAddReviewer {
+			s.prService.notifyPREvent(ctx, domain.PREventAssigned, pr, []string{newReviewerID}, "")
+		} else {
+			s.prService.notifyPREvent(ctx, domain.PREventReassigned, pr, []string{newReviewerID}, review.ReviewerID)
+		}
+	}
+
+	if err := s.prRepo.RecordEscalation(ctx, escalation); err != nil {
+		return false, err
+	}
+
+	s.logger.Warn("review SLA breached, review escalated",
+		zap.String("pr_id", pr.ID),
+		zap.String("team_name", review.TeamName),
+		zap.String("reviewer_id", review.ReviewerID),
+		zap.Time("assigned_at", review.AssignedAt),
+		zap.Time("deadline", review.SLA.Deadline(review.AssignedAt)),
+		zap.String("action", string(action)),
+		zap.String("new_reviewer_id", newReviewerID),
+	)
+
+	return true, nil
+}
+
+// applyEscalation заменяет просрочившего ревьюера reviewerID на newReviewerID или добавляет newReviewerID к PR
+func (s *SLAService) applyEscalation(ctx context.Context, pr *domain.PullRequest, reviewerID string, action domain.SLAAction, newReviewerID string) error {
+	var err error
+	switch action {
+	case domain.SLAActionAddReviewer:
+		err = s.prRepo.AddReviewer(ctx, pr.ID, newReviewerID, SLAActorID, pr.Version)
+	default:
+		err = s.prRepo.ReplaceReviewer(ctx, pr.ID, reviewerID, newReviewerID, SLAActorID, pr.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("apply %s escalation: %w", action, err)
+	}
+	return nil
+}
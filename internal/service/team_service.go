@@ -14,23 +14,39 @@ import (
 )
 
 type TeamService struct {
-	teamRepo repository.TeamRepository
-	userRepo repository.UserRepository
-	logger   *zap.Logger
+	teamRepo    repository.TeamRepository
+	userRepo    repository.UserRepository
+	absenceRepo repository.AbsenceRepository
+	ruleRepo    repository.ReviewerRuleRepository
+	logger      *zap.Logger
 }
 
-func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.UserRepository, logger *zap.Logger) *TeamService {
+func NewTeamService(
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	absenceRepo repository.AbsenceRepository,
+	ruleRepo repository.ReviewerRuleRepository,
+	logger *zap.Logger,
+) *TeamService {
 	return &TeamService{
-		teamRepo: teamRepo,
-		userRepo: userRepo,
-		logger:   logger,
+		teamRepo:    teamRepo,
+		userRepo:    userRepo,
+		absenceRepo: absenceRepo,
+		ruleRepo:    ruleRepo,
+		logger:      logger,
 	}
 }
 
-// CreateTeam создаёт команду с участниками
-func (s *TeamService) CreateTeam(ctx context.Context, input *CreateTeamInput) (*domain.Team, error) {
+// CreateTeam атомарно создаёт команду с участниками.
+// Участники, состоящие в других командах, обрабатываются согласно input.TransferPolicy
+func (s *TeamService) CreateTeam(ctx context.Context, input *CreateTeamInput) (*domain.Team, *domain.TeamTransferReport, error) {
 	if err := input.Validate(); err != nil {
-		return nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+		return nil, nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+	}
+
+	policy := input.TransferPolicy
+	if policy == "" {
+		policy = domain.TransferPolicyReject
 	}
 
 	// Проверяем, существует ли команда с таким именем
@@ -40,70 +56,161 @@ func (s *TeamService) CreateTeam(ctx context.Context, input *CreateTeamInput) (*
 			zap.String("team_name", input.TeamName),
 			zap.Error(err),
 		)
-		return nil, fmt.Errorf("check team: %w", err)
+		return nil, nil, fmt.Errorf("check team: %w", err)
 	}
 
 	if existingTeam != nil {
-		return nil, pkgErrors.ErrTeamExists
+		return nil, nil, pkgErrors.ErrTeamExists
 	}
 
-	// Создаем команду
 	now := time.Now()
 	team := &domain.Team{
-		Name:      input.TeamName,
-		CreatedAt: now,
+		Name:               input.TeamName,
+		ParentName:         input.ParentTeamName,
+		MinSeniorReviewers: input.MinSeniorReviewers,
+		CreatedAt:          now,
+		Members:            make([]*domain.User, len(input.Members)),
 	}
 
-	if err := s.teamRepo.Create(ctx, team); err != nil {
-		if errors.Is(err, repository.ErrAlreadyExists) {
-			return nil, pkgErrors.ErrTeamExists
+	for i, m := range input.Members {
+		team.Members[i] = &domain.User{
+			ID:        m.UserID,
+			Username:  m.Username,
+			Email:     m.Email,
+			IsActive:  m.IsActive,
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
-		s.logger.Error("failed to create team",
-			zap.String("team_name", input.TeamName),
-			zap.Error(err),
-		)
-		return nil, fmt.Errorf("create team: %w", err)
+	}
+
+	// Команда и участники создаются в одной транзакции
+	report, err := s.teamRepo.CreateWithMembers(ctx, team, policy)
+	if err != nil {
+		return nil, nil, s.mapChangeError(err, input.TeamName, "create team")
 	}
 
 	s.logger.Info("team created",
 		zap.String("team_name", team.Name),
 		zap.Int("team_id", team.ID),
+		zap.String("transfer_policy", string(policy)),
+		zap.Int("members_count", len(team.Members)),
+		zap.Int("moved_count", len(report.Moved)),
+		zap.Int("skipped_count", len(report.Skipped)),
 	)
 
-	// Создаем участников
-	var teamMembers []*domain.User
-	for _, m := range input.Members {
-		user := &domain.User{
+	return team, report, nil
+}
+
+// UpdateTeam переименовывает команду, добавляет и удаляет участников.
+// Удалённые участники остаются без команды и снимаются с ревью открытых PR
+func (s *TeamService) UpdateTeam(ctx context.Context, input *UpdateTeamInput) (*domain.Team, *domain.TeamChangeReport, error) {
+	if err := input.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+	}
+
+	policy := input.TransferPolicy
+	if policy == "" {
+		policy = domain.TransferPolicyReject
+	}
+
+	now := time.Now()
+	update := &domain.TeamUpdate{
+		Name:               input.TeamName,
+		NewName:            input.NewTeamName,
+		AddMembers:         make([]*domain.User, len(input.AddMembers)),
+		RemoveMembers:      input.RemoveMembers,
+		TransferPolicy:     policy,
+		ParentName:         input.ParentTeamName,
+		MinSeniorReviewers: input.MinSeniorReviewers,
+		ChatWebhookURL:     input.ChatWebhookURL,
+	}
+
+	for i, m := range input.AddMembers {
+		update.AddMembers[i] = &domain.User{
 			ID:        m.UserID,
 			Username:  m.Username,
-			TeamID:    team.ID,
-			TeamName:  team.Name,
+			Email:     m.Email,
 			IsActive:  m.IsActive,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
+	}
 
-		if err := s.userRepo.Create(ctx, user); err != nil {
-			s.logger.Error("failed to create/update user",
-				zap.String("user_id", user.ID),
-				zap.Error(err),
-			)
-			return nil, fmt.Errorf("create/update user: %w", err)
-		}
+	report, err := s.teamRepo.Update(ctx, update)
+	if err != nil {
+		return nil, nil, s.mapChangeError(err, input.TeamName, "update team")
+	}
 
-		teamMembers = append(teamMembers, user)
+	teamName := input.TeamName
+	if input.NewTeamName != "" {
+		teamName = input.NewTeamName
 	}
 
-	s.logger.Info("team members created/updated",
-		zap.String("team_name", team.Name),
-		zap.Int("count", len(teamMembers)),
+	s.logger.Info("team updated",
+		zap.String("team_name", input.TeamName),
+		zap.String("new_team_name", input.NewTeamName),
+		zap.Int("moved_count", len(report.Moved)),
+		zap.Int("skipped_count", len(report.Skipped)),
+		zap.Int("removed_count", len(report.Removed)),
+		zap.Int("released_reviews_count", len(report.ReleasedReviews)),
 	)
 
-	team.Members = teamMembers
-	return team, nil
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get updated team: %w", err)
+	}
+
+	if err := s.attachHierarchy(ctx, team); err != nil {
+		return nil, nil, err
+	}
+
+	return team, report, nil
 }
 
-// GetTeam возвращает команду по названию
+// DeleteTeam удаляет команду. Участники остаются без команды и снимаются с ревью открытых PR
+func (s *TeamService) DeleteTeam(ctx context.Context, teamName string) (*domain.TeamChangeReport, error) {
+	if teamName == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	report, err := s.teamRepo.Delete(ctx, teamName)
+	if err != nil {
+		return nil, s.mapChangeError(err, teamName, "delete team")
+	}
+
+	s.logger.Info("team deleted",
+		zap.String("team_name", teamName),
+		zap.Int("removed_count", len(report.Removed)),
+		zap.Int("released_reviews_count", len(report.ReleasedReviews)),
+		zap.Strings("reparented_subteams", report.ReparentedSubteams),
+	)
+
+	return report, nil
+}
+
+// mapChangeError мапит ошибки репозитория при изменении команды
+func (s *TeamService) mapChangeError(err error, teamName, action string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return pkgErrors.ErrNotFound
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return pkgErrors.ErrTeamExists
+	case errors.Is(err, repository.ErrConflict):
+		return fmt.Errorf("%w: %v", pkgErrors.ErrUserInOtherTeam, err)
+	case errors.Is(err, repository.ErrUsernameTaken), errors.Is(err, repository.ErrHierarchyCycle):
+		return fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+	case errors.Is(err, repository.ErrParentNotFound):
+		return fmt.Errorf("%w: %v", pkgErrors.ErrNotFound, err)
+	}
+
+	s.logger.Error("failed to "+action,
+		zap.String("team_name", teamName),
+		zap.Error(err),
+	)
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// GetTeam возвращает команду по названию вместе с её местом в иерархии
 func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*domain.Team, error) {
 	if teamName == "" {
 		return nil, pkgErrors.ErrInvalidInput
@@ -121,5 +228,211 @@ func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*domain.Tea
 		return nil, fmt.Errorf("get team: %w", err)
 	}
 
+	if err := s.attachHierarchy(ctx, team); err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// GetTeamTree возвращает иерархию всех команд
+func (s *TeamService) GetTeamTree(ctx context.Context) ([]*domain.TeamNode, error) {
+	nodes, err := s.teamRepo.ListNodes(ctx)
+	if err != nil {
+		s.logger.Error("failed to list teams", zap.Error(err))
+		return nil, fmt.Errorf("list teams: %w", err)
+	}
+
+	return domain.NewTeamTree(nodes).Roots, nil
+}
+
+// attachHierarchy заполняет родителя, цепочку предков и подкоманды команды
+func (s *TeamService) attachHierarchy(ctx context.Context, team *domain.Team) error {
+	nodes, err := s.teamRepo.ListNodes(ctx)
+	if err != nil {
+		s.logger.Error("failed to list teams",
+			zap.String("team_name", team.Name),
+			zap.Error(err),
+		)
+		return fmt.Errorf("list teams: %w", err)
+	}
+
+	tree := domain.NewTeamTree(nodes)
+
+	ancestors := tree.Ancestors(team.ID)
+	team.Ancestors = make([]string, len(ancestors))
+	for i, a := range ancestors {
+		team.Ancestors[i] = a.Name
+	}
+	if len(ancestors) > 0 {
+		team.ParentName = ancestors[len(ancestors)-1].Name
+	}
+
+	if node := tree.Node(team.ID); node != nil {
+		team.Subteams = node.Children
+	}
+
+	return nil
+}
+
+// GetTeamAbsences возвращает текущие отсутствия участников команды и те, что начнутся в ближайшие days дней
+func (s *TeamService) GetTeamAbsences(ctx context.Context, teamName string, days int) ([]*domain.Absence, error) {
+	if teamName == "" || days <= 0 {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	now := time.Now()
+	absences, err := s.absenceRepo.ListByTeamID(ctx, team.ID, now, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, fmt.Errorf("list team absences: %w", err)
+	}
+
+	return absences, nil
+}
+
+// SetReviewerRule задает правило назначения ревьюера автору PR в команде.
+// Оба пользователя должны быть участниками команды; существующее правило для пары заменяется
+func (s *TeamService) SetReviewerRule(ctx context.Context, input *ReviewerRuleInput) (*domain.ReviewerRule, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	members := make(map[string]bool, len(team.Members))
+	for _, m := range team.Members {
+		members[m.ID] = true
+	}
+	for _, userID := range []string{input.AuthorID, input.ReviewerID} {
+		if !members[userID] {
+			return nil, fmt.Errorf("%w: user %s is not a member of team %s", pkgErrors.ErrInvalidInput, userID, team.Name)
+		}
+	}
+
+	rule := &domain.ReviewerRule{
+		TeamID:     team.ID,
+		TeamName:   team.Name,
+		AuthorID:   input.AuthorID,
+		ReviewerID: input.ReviewerID,
+		Kind:       input.Kind,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.ruleRepo.Upsert(ctx, rule); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("save reviewer rule: %w", err)
+	}
+
+	s.logger.Info("reviewer rule set",
+		zap.String("team_name", rule.TeamName),
+		zap.String("author_id", rule.AuthorID),
+		zap.String("reviewer_id", rule.ReviewerID),
+		zap.String("kind", string(rule.Kind)),
+	)
+
+	return rule, nil
+}
+
+// RemoveReviewerRule удаляет правило для пары автор-ревьюер
+func (s *TeamService) RemoveReviewerRule(ctx context.Context, teamName, authorID, reviewerID string) (*domain.ReviewerRule, error) {
+	if teamName == "" || authorID == "" || reviewerID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	rule, err := s.ruleRepo.Delete(ctx, team.ID, authorID, reviewerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("delete reviewer rule: %w", err)
+	}
+
+	s.logger.Info("reviewer rule removed",
+		zap.String("team_name", teamName),
+		zap.String("author_id", authorID),
+		zap.String("reviewer_id", reviewerID),
+	)
+
+	return rule, nil
+}
+
+// GetReviewerRules возвращает правила назначения ревьюеров команды
+func (s *TeamService) GetReviewerRules(ctx context.Context, teamName string) ([]*domain.ReviewerRule, error) {
+	if teamName == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	rules, err := s.ruleRepo.ListByTeamID(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list reviewer rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// SetReviewSLA задаёт SLA ревью команды, nil снимает SLA
+func (s *TeamService) SetReviewSLA(ctx context.Context, teamName string, sla *domain.ReviewSLA) (*domain.Team, error) {
+	if teamName == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+	if sla != nil {
+		if err := sla.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+		}
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	if err := s.teamRepo.UpdateReviewSLA(ctx, team.ID, sla); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("update review SLA: %w", err)
+	}
+
+	s.logger.Info("team review SLA updated",
+		zap.String("team_name", team.Name),
+		zap.Any("review_sla", sla),
+	)
+
+	team.ReviewSLA = sla
 	return team, nil
 }
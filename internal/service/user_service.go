@@ -4,23 +4,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+
 	"github.com/chilly266futon/reviewer-assignment-service/internal/domain"
 	"github.com/chilly266futon/reviewer-assignment-service/internal/repository"
 	pkgErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
 	"go.uber.org/zap"
 )
 
+// maxAbsenceDuration - максимальная длительность одного периода отсутствия
+const maxAbsenceDuration = 366 * 24 * time.Hour
+
 type UserService struct {
-	userRepo repository.UserRepository
-	prRepo   repository.PullRequestRepository
-	logger   *zap.Logger
+	userRepo    repository.UserRepository
+	prRepo      repository.PullRequestRepository
+	absenceRepo repository.AbsenceRepository
+	logger      *zap.Logger
 }
 
-func NewUserService(userRepo repository.UserRepository, prRepo repository.PullRequestRepository, logger *zap.Logger) *UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	absenceRepo repository.AbsenceRepository,
+	logger *zap.Logger,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		prRepo:   prRepo,
-		logger:   logger,
+		userRepo:    userRepo,
+		prRepo:      prRepo,
+		absenceRepo: absenceRepo,
+		logger:      logger,
 	}
 }
 
@@ -74,3 +86,233 @@ func (s *UserService) GetUserReviews(ctx context.Context, userID string) ([]*dom
 
 	return prs, nil
 }
+
+// AddAbsence регистрирует период отсутствия. Во время отсутствия пользователь не назначается ревьюером
+func (s *UserService) AddAbsence(ctx context.Context, input *AddAbsenceInput) (*domain.Absence, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+	}
+
+	now := time.Now()
+	if !input.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: ends_at must be in the future", pkgErrors.ErrInvalidInput)
+	}
+
+	absence := &domain.Absence{
+		UserID:    input.UserID,
+		StartsAt:  input.StartsAt,
+		EndsAt:    input.EndsAt,
+		Reason:    input.Reason,
+		CreatedAt: now,
+	}
+
+	if err := s.absenceRepo.Create(ctx, absence); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		s.logger.Error("failed to add absence",
+			zap.String("user_id", input.UserID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("add absence: %w", err)
+	}
+
+	s.logger.Info("absence added",
+		zap.String("user_id", absence.UserID),
+		zap.Int64("absence_id", absence.ID),
+		zap.Time("starts_at", absence.StartsAt),
+		zap.Time("ends_at", absence.EndsAt),
+	)
+
+	return absence, nil
+}
+
+// CancelAbsence отменяет период отсутствия пользователя
+func (s *UserService) CancelAbsence(ctx context.Context, userID string, absenceID int64) (*domain.Absence, error) {
+	if userID == "" || absenceID <= 0 {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	absence, err := s.absenceRepo.Delete(ctx, userID, absenceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		s.logger.Error("failed to cancel absence",
+			zap.String("user_id", userID),
+			zap.Int64("absence_id", absenceID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("cancel absence: %w", err)
+	}
+
+	s.logger.Info("absence cancelled",
+		zap.String("user_id", userID),
+		zap.Int64("absence_id", absenceID),
+	)
+
+	return absence, nil
+}
+
+// GetAbsences возвращает текущие и будущие отсутствия пользователя
+func (s *UserService) GetAbsences(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	if userID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	absences, err := s.absenceRepo.ListByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("list absences: %w", err)
+	}
+
+	return absences, nil
+}
+
+// SetWorkSchedule задает рабочее время пользователя. nil сбрасывает расписание:
+// пользователь считается доступным в любое время
+func (s *UserService) SetWorkSchedule(ctx context.Context, userID string, schedule *domain.WorkSchedule) (*domain.User, error) {
+	if userID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	if schedule != nil {
+		if err := schedule.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+		}
+	}
+
+	if err := s.userRepo.UpdateWorkSchedule(ctx, userID, schedule); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		s.logger.Error("failed to update work schedule",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("update work schedule: %w", err)
+	}
+
+	s.logger.Info("work schedule updated",
+		zap.String("user_id", userID),
+		zap.Bool("cleared", schedule == nil),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated user: %w", err)
+	}
+
+	return user, nil
+}
+
+// SetQuietHours задает тихие часы пользователя, в которые ему не отправляются сводки. nil сбрасывает их
+func (s *UserService) SetQuietHours(ctx context.Context, userID string, quietHours *domain.QuietHours) (*domain.User, error) {
+	if userID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	if quietHours != nil {
+		if err := quietHours.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+		}
+	}
+
+	if err := s.userRepo.UpdateQuietHours(ctx, userID, quietHours); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		s.logger.Error("failed to update quiet hours",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("update quiet hours: %w", err)
+	}
+
+	s.logger.Info("quiet hours updated",
+		zap.String("user_id", userID),
+		zap.Bool("cleared", quietHours == nil),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated user: %w", err)
+	}
+
+	return user, nil
+}
+
+// SetReviewCapacity задает лимит одновременно открытых ревью пользователя. nil снимает ограничение.
+// Пользователь, достигший лимита, не назначается ревьюером
+func (s *UserService) SetReviewCapacity(ctx context.Context, userID string, maxOpenReviews *int) (*domain.User, error) {
+	if userID == "" {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	if maxOpenReviews != nil && *maxOpenReviews < 0 {
+		return nil, fmt.Errorf("%w: max_open_reviews must not be negative", pkgErrors.ErrInvalidInput)
+	}
+
+	if err := s.userRepo.UpdateMaxOpenReviews(ctx, userID, maxOpenReviews); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		s.logger.Error("failed to update review capacity",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("update review capacity: %w", err)
+	}
+
+	fields := []zap.Field{zap.String("user_id", userID)}
+	if maxOpenReviews != nil {
+		fields = append(fields, zap.Int("max_open_reviews", *maxOpenReviews))
+	}
+	s.logger.Info("review capacity updated", fields...)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated user: %w", err)
+	}
+
+	return user, nil
+}
+
+// UpdateSettings изменяет настройки пользователя: параметры выбора ревьюеров и отказ от сводок
+func (s *UserService) UpdateSettings(ctx context.Context, userID string, settings *domain.UserSettings) (*domain.User, error) {
+	if userID == "" || settings == nil || settings.IsEmpty() {
+		return nil, pkgErrors.ErrInvalidInput
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkgErrors.ErrInvalidInput, err)
+	}
+
+	if err := s.userRepo.UpdateSettings(ctx, userID, settings); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, pkgErrors.ErrNotFound
+		}
+		s.logger.Error("failed to update user settings",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("update user settings: %w", err)
+	}
+
+	s.logger.Info("user settings updated",
+		zap.String("user_id", userID),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get updated user: %w", err)
+	}
+
+	return user, nil
+}
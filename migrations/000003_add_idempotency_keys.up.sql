@@ -0,0 +1,15 @@
+-- Сохранённые ответы для запросов с заголовком Idempotency-Key
+CREATE TABLE idempotency_keys (
+    key           VARCHAR(255) NOT NULL,
+    scope         VARCHAR(255) NOT NULL,
+    request_hash  VARCHAR(64)  NOT NULL,
+    status_code   INTEGER,
+    response_body BYTEA,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMPTZ  NOT NULL, -- вычисляется в сервисе и сравнивается с NOW() БД
+    locked_until  TIMESTAMPTZ  NOT NULL, -- после этого момента ключ без ответа может занять повторный запрос
+    PRIMARY KEY (key, scope)
+);
+
+-- Индекс для очистки просроченных ключей
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,5 @@
+-- Версия PR для оптимистичных блокировок (отдаётся клиентам как ETag)
+ALTER TABLE pull_requests ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- ETag сохранённого ответа, чтобы повтор запроса с Idempotency-Key вернул ту же версию
+ALTER TABLE idempotency_keys ADD COLUMN etag VARCHAR(64);
@@ -0,0 +1,6 @@
+ALTER TABLE users DROP CONSTRAINT users_team_id_fkey;
+ALTER TABLE users
+    ADD CONSTRAINT users_team_id_fkey FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE;
+
+-- Не выполнится, если остались пользователи без команды
+ALTER TABLE users ALTER COLUMN team_id SET NOT NULL;
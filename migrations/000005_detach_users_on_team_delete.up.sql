@@ -0,0 +1,7 @@
+-- Удаление команды не должно удалять пользователей (и ломать ссылки из pr_reviewers и pull_requests):
+-- участники удалённой команды остаются без команды
+ALTER TABLE users ALTER COLUMN team_id DROP NOT NULL;
+
+ALTER TABLE users DROP CONSTRAINT users_team_id_fkey;
+ALTER TABLE users
+    ADD CONSTRAINT users_team_id_fkey FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE SET NULL;
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN team_id INTEGER REFERENCES teams (id) ON DELETE SET NULL;
+
+-- Пользователь остаётся в команде, в которую вступил первой
+UPDATE users u
+SET team_id = m.team_id
+FROM (
+    SELECT DISTINCT ON (user_id) user_id, team_id
+    FROM team_memberships
+    ORDER BY user_id, joined_at, team_id
+) m
+WHERE m.user_id = u.id;
+
+CREATE INDEX idx_users_team_id ON users(team_id);
+CREATE INDEX idx_users_team_active ON users(team_id, is_active);
+
+DROP INDEX IF EXISTS idx_pr_team_id;
+ALTER TABLE pull_requests DROP COLUMN team_id;
+
+DROP TABLE IF EXISTS team_memberships;
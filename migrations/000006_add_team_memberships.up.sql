@@ -0,0 +1,28 @@
+-- Пользователь может состоять в нескольких командах
+CREATE TABLE team_memberships (
+    team_id   INTEGER      NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id   VARCHAR(100) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    joined_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+-- Индекс для поиска команд пользователя
+CREATE INDEX idx_team_memberships_user_id ON team_memberships(user_id);
+
+INSERT INTO team_memberships (team_id, user_id, joined_at)
+SELECT team_id, id, created_at
+FROM users
+WHERE team_id IS NOT NULL;
+
+-- Команда, из которой назначаются ревьюеры PR
+ALTER TABLE pull_requests ADD COLUMN team_id INTEGER REFERENCES teams (id) ON DELETE SET NULL;
+
+UPDATE pull_requests pr
+SET team_id = u.team_id
+FROM users u
+WHERE pr.author_id = u.id;
+
+CREATE INDEX idx_pr_team_id ON pull_requests(team_id);
+
+-- Индексы idx_users_team_id и idx_users_team_active удаляются вместе с колонкой
+ALTER TABLE users DROP COLUMN team_id;
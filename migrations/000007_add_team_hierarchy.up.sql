@@ -0,0 +1,5 @@
+-- Иерархия команд: организация → отдел → команда.
+-- При удалении команды её подкоманды переносятся к её родителю (см. TeamRepository.Delete)
+ALTER TABLE teams ADD COLUMN parent_id INTEGER REFERENCES teams (id);
+
+CREATE INDEX idx_teams_parent_id ON teams(parent_id);
@@ -0,0 +1,14 @@
+-- Запланированное отсутствие пользователей (отпуск, больничный и т.п.).
+-- Во время отсутствия пользователь не назначается ревьюером
+CREATE TABLE user_absences (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    VARCHAR(100) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    starts_at  TIMESTAMPTZ  NOT NULL,
+    ends_at    TIMESTAMPTZ  NOT NULL,
+    reason     VARCHAR(200) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+-- Индекс для поиска текущих и будущих отсутствий пользователя
+CREATE INDEX idx_user_absences_user_ends_at ON user_absences(user_id, ends_at);
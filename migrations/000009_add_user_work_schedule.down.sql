@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_work_schedule_complete,
+    DROP COLUMN IF EXISTS work_days,
+    DROP COLUMN IF EXISTS work_end,
+    DROP COLUMN IF EXISTS work_start,
+    DROP COLUMN IF EXISTS timezone;
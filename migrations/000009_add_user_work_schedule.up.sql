@@ -0,0 +1,11 @@
+-- Рабочее время пользователя в его часовом поясе.
+-- Если расписание не задано, пользователь считается доступным в любое время
+ALTER TABLE users
+    ADD COLUMN timezone   VARCHAR(64),
+    ADD COLUMN work_start TIME,
+    ADD COLUMN work_end   TIME,
+    ADD COLUMN work_days  SMALLINT[],
+    ADD CONSTRAINT users_work_schedule_complete CHECK (
+        (timezone IS NULL AND work_start IS NULL AND work_end IS NULL AND work_days IS NULL)
+        OR (timezone IS NOT NULL AND work_start IS NOT NULL AND work_end IS NOT NULL AND work_days IS NOT NULL)
+    );
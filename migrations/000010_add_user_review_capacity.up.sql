@@ -0,0 +1,3 @@
+-- Максимальное число одновременно открытых ревью пользователя. NULL - без ограничения
+ALTER TABLE users
+    ADD COLUMN max_open_reviews INTEGER CHECK (max_open_reviews >= 0);
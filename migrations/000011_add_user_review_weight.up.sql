@@ -0,0 +1,4 @@
+-- Вес пользователя при случайном выборе ревьюеров: 2 - выбирается вдвое чаще, 0.5 - вдвое реже
+ALTER TABLE users
+    ADD COLUMN review_weight DOUBLE PRECISION NOT NULL DEFAULT 1
+        CHECK (review_weight > 0 AND review_weight <= 100);
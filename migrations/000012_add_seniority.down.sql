@@ -0,0 +1,2 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS min_senior_reviewers;
+ALTER TABLE users DROP COLUMN IF EXISTS seniority;
@@ -0,0 +1,8 @@
+-- Уровень пользователя и правило команды: сколько из назначенных ревьюеров должны быть senior
+ALTER TABLE users
+    ADD COLUMN seniority VARCHAR(20) NOT NULL DEFAULT 'middle'
+        CHECK (seniority IN ('junior', 'middle', 'senior'));
+
+ALTER TABLE teams
+    ADD COLUMN min_senior_reviewers SMALLINT NOT NULL DEFAULT 0
+        CHECK (min_senior_reviewers >= 0);
@@ -0,0 +1,12 @@
+-- Правила назначения ревьюеров для пар автор-ревьюер внутри команды:
+-- prefer - ревьюер назначается автору в первую очередь (например, наставник),
+-- forbid - ревьюер никогда не назначается автору (например, конфликт интересов)
+CREATE TABLE team_reviewer_rules (
+    team_id     INTEGER      NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    author_id   VARCHAR(100) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(100) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind        VARCHAR(10)  NOT NULL CHECK (kind IN ('prefer', 'forbid')),
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, author_id, reviewer_id),
+    CHECK (author_id <> reviewer_id)
+);
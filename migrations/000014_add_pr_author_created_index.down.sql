@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_pr_author_created_at;
@@ -0,0 +1,2 @@
+-- Индекс для поиска недавних PR автора (ротация ревьюеров)
+CREATE INDEX idx_pr_author_created_at ON pull_requests(author_id, created_at DESC);
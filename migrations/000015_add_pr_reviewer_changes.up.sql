@@ -0,0 +1,12 @@
+-- Журнал ручных изменений ревьюеров PR: кто (actor_id) добавил или снял ревьюера.
+-- actor_id не ссылается на users: изменение может выполнить внешняя интеграция
+CREATE TABLE pr_reviewer_changes (
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(100) NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    user_id         VARCHAR(100) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    action          VARCHAR(10)  NOT NULL CHECK (action IN ('added', 'removed')),
+    actor_id        VARCHAR(100) NOT NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pr_reviewer_changes_pr_created_at ON pr_reviewer_changes(pull_request_id, created_at);
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS review_escalations;
+
+DROP INDEX IF EXISTS idx_pr_reviewers_pending;
+
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS reviewed_at,
+    ALTER COLUMN assigned_at TYPE TIMESTAMP;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS sla_action,
+    DROP COLUMN IF EXISTS review_sla_hours;
@@ -0,0 +1,29 @@
+-- SLA ревью команды: срок первого ревью в рабочих часах и действие при его нарушении
+ALTER TABLE teams
+    ADD COLUMN review_sla_hours INTEGER CHECK (review_sla_hours BETWEEN 1 AND 720),
+    ADD COLUMN sla_action       VARCHAR(20) NOT NULL DEFAULT 'reassign'
+        CHECK (sla_action IN ('reassign', 'add_reviewer'));
+
+-- reviewed_at - ревьюер отправил ревью, escalated_at - ревью просрочено и эскалировано.
+-- assigned_at сравнивается со сроком SLA, поэтому хранится с часовым поясом;
+-- существующие значения записаны через NOW() и интерпретируются в часовом поясе сервера БД
+ALTER TABLE pr_reviewers
+    ALTER COLUMN assigned_at TYPE TIMESTAMPTZ,
+    ADD COLUMN reviewed_at  TIMESTAMPTZ,
+    ADD COLUMN escalated_at TIMESTAMPTZ;
+
+-- Индекс для поиска ожидающих ревью
+CREATE INDEX idx_pr_reviewers_pending ON pr_reviewers(assigned_at)
+    WHERE reviewed_at IS NULL AND escalated_at IS NULL;
+
+-- События эскалации просроченных ревью
+CREATE TABLE review_escalations (
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(100) NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    reviewer_id     VARCHAR(100) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    action          VARCHAR(20)  NOT NULL CHECK (action IN ('reassign', 'add_reviewer')),
+    new_reviewer_id VARCHAR(100) REFERENCES users (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_review_escalations_pr ON review_escalations(pull_request_id, created_at);
@@ -0,0 +1,14 @@
+-- История запусков фоновых задач. Запуск в статусе running, не завершённый из-за остановки
+-- экземпляра, помечается failed при следующем запуске той же задачи
+CREATE TABLE job_runs (
+    id          BIGSERIAL PRIMARY KEY,
+    job_name    VARCHAR(100) NOT NULL,
+    trigger     VARCHAR(10)  NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    status      VARCHAR(10)  NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    processed   INTEGER      NOT NULL DEFAULT 0,
+    error       TEXT,
+    started_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_job_runs_job_started_at ON job_runs(job_name, started_at DESC);
@@ -0,0 +1,7 @@
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_quiet_hours_complete,
+    DROP COLUMN IF EXISTS last_digest_at,
+    DROP COLUMN IF EXISTS quiet_end,
+    DROP COLUMN IF EXISTS quiet_start,
+    DROP COLUMN IF EXISTS quiet_timezone,
+    DROP COLUMN IF EXISTS digest_opt_out;
@@ -0,0 +1,12 @@
+-- Сводки ожидающих ревью: отказ от рассылки, тихие часы пользователя
+-- и время последней отправленной ему сводки
+ALTER TABLE users
+    ADD COLUMN digest_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN quiet_timezone VARCHAR(64),
+    ADD COLUMN quiet_start    TIME,
+    ADD COLUMN quiet_end      TIME,
+    ADD COLUMN last_digest_at TIMESTAMPTZ,
+    ADD CONSTRAINT users_quiet_hours_complete CHECK (
+        (quiet_timezone IS NULL AND quiet_start IS NULL AND quiet_end IS NULL)
+        OR (quiet_timezone IS NOT NULL AND quiet_start IS NOT NULL AND quiet_end IS NOT NULL)
+    );
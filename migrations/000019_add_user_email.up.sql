@@ -0,0 +1,3 @@
+-- Адрес для email-уведомлений о назначении ревьюером и сводок ожидающих ревью
+ALTER TABLE users
+    ADD COLUMN email VARCHAR(254);
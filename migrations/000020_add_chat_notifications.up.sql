@@ -0,0 +1,7 @@
+-- Входящий вебхук Slack/Mattermost команды для уведомлений о PR
+ALTER TABLE teams
+    ADD COLUMN chat_webhook_url TEXT;
+
+-- Упоминание пользователя в чате, например <@U024BE7LH> для Slack или @bob для Mattermost
+ALTER TABLE users
+    ADD COLUMN chat_handle VARCHAR(100);
@@ -0,0 +1,587 @@
+// Package client - типизированный Go клиент для HTTP API reviewer-assignment-service
+// (см. api/openapi.yaml)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client - клиент HTTP API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option настраивает клиент
+type Option func(*Client)
+
+// WithHTTPClient задаёт HTTP клиент (по умолчанию - с таймаутом 30 секунд)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New создаёт клиент для сервиса по адресу baseURL, например "http://localhost:8080"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// CallOption настраивает отдельный запрос
+type CallOption func(*http.Request)
+
+// WithIdempotencyKey передаёт заголовок Idempotency-Key, чтобы повтор запроса был безопасным
+func WithIdempotencyKey(key string) CallOption {
+	return func(r *http.Request) {
+		r.Header.Set("Idempotency-Key", key)
+	}
+}
+
+// WithIfMatch передаёт ожидаемую версию PR в заголовке If-Match
+func WithIfMatch(version int) CallOption {
+	return func(r *http.Request) {
+		r.Header.Set("If-Match", strconv.Quote(strconv.Itoa(version)))
+	}
+}
+
+// Health проверяет доступность сервиса и базы данных
+func (c *Client) Health(ctx context.Context) error {
+	var resp struct {
+		Status string `json:"status"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, &resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddTeam создаёт команду с участниками
+func (c *Client) AddTeam(ctx context.Context, req *AddTeamRequest, opts ...CallOption) (*AddTeamResult, error) {
+	var resp AddTeamResult
+	if err := c.do(ctx, http.MethodPost, "/team/add", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTeam возвращает команду с участниками
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*Team, error) {
+	var team Team
+	query := url.Values{"team_name": {teamName}}
+	if err := c.do(ctx, http.MethodGet, "/team/get", query, nil, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// GetTeamTree возвращает иерархию всех команд
+func (c *Client) GetTeamTree(ctx context.Context) ([]*TeamNode, error) {
+	var resp struct {
+		Teams []*TeamNode `json:"teams"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/team/tree", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Teams, nil
+}
+
+// UpdateTeam переименовывает команду, добавляет и удаляет участников
+func (c *Client) UpdateTeam(ctx context.Context, req *UpdateTeamRequest, opts ...CallOption) (*UpdateTeamResult, error) {
+	var resp UpdateTeamResult
+	if err := c.do(ctx, http.MethodPost, "/team/update", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteTeam удаляет команду. Участники остаются без команды
+func (c *Client) DeleteTeam(ctx context.Context, teamName string, opts ...CallOption) (*TeamChanges, error) {
+	req := struct {
+		TeamName string `json:"team_name"`
+	}{TeamName: teamName}
+
+	var resp struct {
+		Changes *TeamChanges `json:"changes"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/team/delete", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Changes, nil
+}
+
+// SetReviewerRule задает правило назначения ревьюера автору PR (ReviewerRulePrefer или ReviewerRuleForbid)
+func (c *Client) SetReviewerRule(ctx context.Context, teamName, authorID, reviewerID, kind string, opts ...CallOption) (*ReviewerRule, error) {
+	req := struct {
+		TeamName   string `json:"team_name"`
+		AuthorID   string `json:"author_id"`
+		ReviewerID string `json:"reviewer_id"`
+		Kind       string `json:"kind"`
+	}{TeamName: teamName, AuthorID: authorID, ReviewerID: reviewerID, Kind: kind}
+
+	var resp struct {
+		Rule *ReviewerRule `json:"rule"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/team/setReviewerRule", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Rule, nil
+}
+
+// RemoveReviewerRule удаляет правило для пары автор-ревьюер и возвращает его
+func (c *Client) RemoveReviewerRule(ctx context.Context, teamName, authorID, reviewerID string, opts ...CallOption) (*ReviewerRule, error) {
+	req := struct {
+		TeamName   string `json:"team_name"`
+		AuthorID   string `json:"author_id"`
+		ReviewerID string `json:"reviewer_id"`
+	}{TeamName: teamName, AuthorID: authorID, ReviewerID: reviewerID}
+
+	var resp struct {
+		Rule *ReviewerRule `json:"rule"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/team/removeReviewerRule", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Rule, nil
+}
+
+// GetReviewerRules возвращает правила назначения ревьюеров команды
+func (c *Client) GetReviewerRules(ctx context.Context, teamName string) ([]*ReviewerRule, error) {
+	var resp struct {
+		Rules []*ReviewerRule `json:"rules"`
+	}
+	query := url.Values{"team_name": {teamName}}
+	if err := c.do(ctx, http.MethodGet, "/team/reviewerRules", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Rules, nil
+}
+
+// SetReviewSLA задаёт SLA ревью команды, nil снимает SLA
+func (c *Client) SetReviewSLA(ctx context.Context, teamName string, sla *ReviewSLA, opts ...CallOption) (*Team, error) {
+	req := struct {
+		TeamName  string     `json:"team_name"`
+		ReviewSLA *ReviewSLA `json:"review_sla"`
+	}{TeamName: teamName, ReviewSLA: sla}
+
+	var resp struct {
+		Team *Team `json:"team"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/team/setReviewSLA", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Team, nil
+}
+
+// SetIsActive устанавливает флаг активности пользователя
+func (c *Client) SetIsActive(ctx context.Context, userID string, isActive bool, opts ...CallOption) (*User, error) {
+	req := struct {
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
+	}{UserID: userID, IsActive: isActive}
+
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/setIsActive", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// SetWorkSchedule задает рабочее время пользователя. nil сбрасывает расписание
+func (c *Client) SetWorkSchedule(ctx context.Context, userID string, schedule *WorkSchedule, opts ...CallOption) (*User, error) {
+	req := struct {
+		UserID       string        `json:"user_id"`
+		WorkSchedule *WorkSchedule `json:"work_schedule"`
+	}{UserID: userID, WorkSchedule: schedule}
+
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/setWorkSchedule", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// SetQuietHours задает тихие часы пользователя, в которые ему не отправляются сводки. nil сбрасывает их
+func (c *Client) SetQuietHours(ctx context.Context, userID string, quietHours *QuietHours, opts ...CallOption) (*User, error) {
+	req := struct {
+		UserID     string      `json:"user_id"`
+		QuietHours *QuietHours `json:"quiet_hours"`
+	}{UserID: userID, QuietHours: quietHours}
+
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/setQuietHours", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// SetReviewCapacity задает лимит одновременно открытых ревью пользователя. nil снимает ограничение
+func (c *Client) SetReviewCapacity(ctx context.Context, userID string, maxOpenReviews *int, opts ...CallOption) (*User, error) {
+	req := struct {
+		UserID         string `json:"user_id"`
+		MaxOpenReviews *int   `json:"max_open_reviews"`
+	}{UserID: userID, MaxOpenReviews: maxOpenReviews}
+
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/setReviewCapacity", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// UpdateSettings изменяет настройки пользователя
+func (c *Client) UpdateSettings(ctx context.Context, userID string, settings UserSettings, opts ...CallOption) (*User, error) {
+	req := struct {
+		UserID string `json:"user_id"`
+		UserSettings
+	}{UserID: userID, UserSettings: settings}
+
+	var resp struct {
+		User  *User  `json:"user"`
+		Email string `json:"email"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/updateSettings", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	if resp.User != nil {
+		resp.User.Email = resp.Email
+	}
+	return resp.User, nil
+}
+
+// GetReview возвращает PR'ы, где пользователь назначен ревьюером
+func (c *Client) GetReview(ctx context.Context, userID string) (*UserReviews, error) {
+	var resp UserReviews
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/getReview", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddAbsence регистрирует период отсутствия: в это время пользователь не назначается ревьюером
+func (c *Client) AddAbsence(ctx context.Context, req *AddAbsenceRequest, opts ...CallOption) (*Absence, error) {
+	var resp struct {
+		Absence *Absence `json:"absence"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/addAbsence", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Absence, nil
+}
+
+// CancelAbsence отменяет период отсутствия
+func (c *Client) CancelAbsence(ctx context.Context, userID string, absenceID int64, opts ...CallOption) (*Absence, error) {
+	req := struct {
+		UserID    string `json:"user_id"`
+		AbsenceID int64  `json:"absence_id"`
+	}{UserID: userID, AbsenceID: absenceID}
+
+	var resp struct {
+		Absence *Absence `json:"absence"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/cancelAbsence", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Absence, nil
+}
+
+// GetAbsences возвращает текущие и будущие отсутствия пользователя
+func (c *Client) GetAbsences(ctx context.Context, userID string) ([]*Absence, error) {
+	var resp struct {
+		Absences []*Absence `json:"absences"`
+	}
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/getAbsences", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Absences, nil
+}
+
+// GetTeamAbsences возвращает текущие отсутствия участников команды и те, что начнутся в ближайшие days дней.
+// days = 0 - значение по умолчанию сервера (30 дней)
+func (c *Client) GetTeamAbsences(ctx context.Context, teamName string, days int) ([]*Absence, error) {
+	var resp struct {
+		Absences []*Absence `json:"absences"`
+	}
+	query := url.Values{"team_name": {teamName}}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	if err := c.do(ctx, http.MethodGet, "/team/absences", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Absences, nil
+}
+
+// CreatePullRequest создаёт PR с автоматическим назначением ревьюеров
+func (c *Client) CreatePullRequest(ctx context.Context, req *CreatePullRequestRequest, opts ...CallOption) (*PullRequest, error) {
+	result, err := c.CreatePullRequestExplained(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return result.PullRequest, nil
+}
+
+// CreatePullRequestExplained создаёт PR и возвращает его вместе с объяснением выбора ревьюеров
+func (c *Client) CreatePullRequestExplained(ctx context.Context, req *CreatePullRequestRequest, opts ...CallOption) (*CreatePullRequestResult, error) {
+	var resp CreatePullRequestResult
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/create", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PreviewPullRequest подбирает ревьюеров для PR автора, не создавая PR.
+// teamName обязателен, если автор состоит в нескольких командах
+func (c *Client) PreviewPullRequest(ctx context.Context, authorID, teamName string, opts ...CallOption) (*PreviewPullRequestResult, error) {
+	req := struct {
+		AuthorID string `json:"author_id"`
+		TeamName string `json:"team_name,omitempty"`
+	}{AuthorID: authorID, TeamName: teamName}
+
+	var resp PreviewPullRequestResult
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/preview", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// MergePullRequest помечает PR как MERGED
+func (c *Client) MergePullRequest(ctx context.Context, prID string, opts ...CallOption) (*PullRequest, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+	}{PullRequestID: prID}
+
+	var resp struct {
+		PR *PullRequest `json:"pull_request"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/merge", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.PR, nil
+}
+
+// ReassignReviewer заменяет ревьюера на другого участника его команды
+func (c *Client) ReassignReviewer(ctx context.Context, prID, oldUserID string, opts ...CallOption) (*ReassignResult, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+	}{PullRequestID: prID, OldUserID: oldUserID}
+
+	var resp ReassignResult
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/reassign", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddReviewer вручную назначает ревьюера на PR. actorID - кто вносит изменение
+func (c *Client) AddReviewer(ctx context.Context, prID, userID, actorID string, opts ...CallOption) (*PullRequest, error) {
+	return c.changeReviewer(ctx, "/pullRequest/addReviewer", prID, userID, actorID, opts...)
+}
+
+// RemoveReviewer снимает ревьюера с PR без замены. actorID - кто вносит изменение
+func (c *Client) RemoveReviewer(ctx context.Context, prID, userID, actorID string, opts ...CallOption) (*PullRequest, error) {
+	return c.changeReviewer(ctx, "/pullRequest/removeReviewer", prID, userID, actorID, opts...)
+}
+
+func (c *Client) changeReviewer(ctx context.Context, path, prID, userID, actorID string, opts ...CallOption) (*PullRequest, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+		ActorID       string `json:"actor_id"`
+	}{PullRequestID: prID, UserID: userID, ActorID: actorID}
+
+	var resp struct {
+		PR *PullRequest `json:"pull_request"`
+	}
+	if err := c.do(ctx, http.MethodPost, path, nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.PR, nil
+}
+
+// ReassignReviewerTo передаёт ревью от oldUserID указанному newUserID без автоматического выбора
+func (c *Client) ReassignReviewerTo(ctx context.Context, prID, oldUserID, newUserID string, opts ...CallOption) (*ReassignResult, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+		NewUserID     string `json:"new_user_id"`
+	}{PullRequestID: prID, OldUserID: oldUserID, NewUserID: newUserID}
+
+	var resp ReassignResult
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/reassign", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SubmitReview отмечает, что ревьюер отправил ревью, и останавливает отсчёт SLA
+func (c *Client) SubmitReview(ctx context.Context, prID, userID string, opts ...CallOption) (*PullRequest, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+	}{PullRequestID: prID, UserID: userID}
+
+	var resp struct {
+		PR *PullRequest `json:"pull_request"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/submitReview", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.PR, nil
+}
+
+// GetEscalations возвращает эскалации просроченных ревью PR
+func (c *Client) GetEscalations(ctx context.Context, prID string) ([]*ReviewEscalation, error) {
+	var resp struct {
+		Escalations []*ReviewEscalation `json:"escalations"`
+	}
+	query := url.Values{"pull_request_id": {prID}}
+	if err := c.do(ctx, http.MethodGet, "/pullRequest/escalations", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Escalations, nil
+}
+
+// ListJobs возвращает фоновые задачи сервиса и их последние запуски
+func (c *Client) ListJobs(ctx context.Context) ([]*Job, error) {
+	var resp struct {
+		Jobs []*Job `json:"jobs"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/jobs", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Jobs, nil
+}
+
+// GetJobRuns возвращает последние запуски задачи, новые первыми. limit <= 0 - значение сервера по умолчанию
+func (c *Client) GetJobRuns(ctx context.Context, jobName string, limit int) ([]*JobRun, error) {
+	var resp struct {
+		Runs []*JobRun `json:"runs"`
+	}
+	query := url.Values{"job_name": {jobName}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/jobRuns", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Runs, nil
+}
+
+// TriggerJob запускает задачу вне расписания и возвращает созданный запуск.
+// Задача выполняется в фоне, результат - в GetJobRuns
+func (c *Client) TriggerJob(ctx context.Context, jobName string, opts ...CallOption) (*JobRun, error) {
+	req := struct {
+		JobName string `json:"job_name"`
+	}{JobName: jobName}
+
+	var resp struct {
+		Run *JobRun `json:"run"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/admin/triggerJob", nil, req, &resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Run, nil
+}
+
+// do выполняет запрос и декодирует ответ в out, а ответ с ошибкой - в *APIError
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}, opts ...CallOption) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp.StatusCode, data)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// decodeError декодирует ответ с ошибкой
+func decodeError(statusCode int, data []byte) error {
+	var errResp errorResponse
+	if err := json.Unmarshal(data, &errResp); err != nil || errResp.Error.Code == "" {
+		return &APIError{
+			StatusCode: statusCode,
+			Code:       http.StatusText(statusCode),
+			Message:    strings.TrimSpace(string(data)),
+		}
+	}
+
+	return &APIError{
+		StatusCode: statusCode,
+		Code:       errResp.Error.Code,
+		Message:    errResp.Error.Message,
+	}
+}
@@ -0,0 +1,103 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chilly266futon/reviewer-assignment-service/pkg/client"
+	serviceErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/pullRequest/create", r.URL.Path)
+		assert.Equal(t, "key-1", r.Header.Get("Idempotency-Key"))
+
+		var req client.CreatePullRequestRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "pr1", req.PullRequestID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"pull_request":{"pull_request_id":"pr1","pull_request_name":"Fix","author_id":"u1","status":"OPEN","assigned_reviewers":["u2"],"version":1}}`))
+	}))
+	defer srv.Close()
+
+	c := client.New(srv.URL)
+	pr, err := c.CreatePullRequest(context.Background(), &client.CreatePullRequestRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "Fix",
+		AuthorID:        "u1",
+	}, client.WithIdempotencyKey("key-1"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "pr1", pr.ID)
+	assert.Equal(t, client.StatusOpen, pr.Status)
+	assert.Equal(t, []string{"u2"}, pr.AssignedReviewers)
+	assert.Equal(t, 1, pr.Version)
+}
+
+func TestClient_ReassignReviewer_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `"3"`, r.Header.Get("If-Match"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":{"code":"NO_CANDIDATE","message":"no candidate available for reassignment"}}`))
+	}))
+	defer srv.Close()
+
+	c := client.New(srv.URL)
+	_, err := c.ReassignReviewer(context.Background(), "pr1", "u2", client.WithIfMatch(3))
+	require.Error(t, err)
+
+	assert.True(t, errors.Is(err, serviceErrors.ErrNoCandidate))
+
+	var apiErr *client.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, serviceErrors.CodeNoCandidate, apiErr.Code)
+}
+
+func TestClient_GetTeam(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/team/get", r.URL.Path)
+		assert.Equal(t, "backend", r.URL.Query().Get("team_name"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"team_name":"backend","members":[{"id":"u1","username":"Alice","team_name":"backend","is_active":true}]}`))
+	}))
+	defer srv.Close()
+
+	team, err := client.New(srv.URL).GetTeam(context.Background(), "backend")
+	require.NoError(t, err)
+
+	assert.Equal(t, "backend", team.TeamName)
+	require.Len(t, team.Members, 1)
+	assert.Equal(t, "u1", team.Members[0].ID)
+}
+
+func TestClient_UpdateSettingsReturnsEmail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/updateSettings", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user":{"id":"u1","username":"Alice","team_name":"backend","is_active":true,"email_configured":true},"email":"alice@example.com"}`))
+	}))
+	defer srv.Close()
+
+	email := "alice@example.com"
+	user, err := client.New(srv.URL).UpdateSettings(context.Background(), "u1", client.UserSettings{Email: &email})
+	require.NoError(t, err)
+
+	assert.True(t, user.EmailConfigured)
+	assert.Equal(t, "alice@example.com", user.Email)
+}
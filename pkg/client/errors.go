@@ -0,0 +1,32 @@
+package client
+
+import (
+	"fmt"
+
+	serviceErrors "github.com/chilly266futon/reviewer-assignment-service/pkg/errors"
+)
+
+// APIError - ошибка, которую вернул сервис.
+// Поддерживает errors.Is с ошибками из pkg/errors, например errors.Is(err, errors.ErrNoCandidate)
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Unwrap возвращает доменную ошибку, соответствующую коду
+func (e *APIError) Unwrap() error {
+	return serviceErrors.MapCodeToError(e.Code)
+}
+
+// errorResponse - формат ошибки API
+type errorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
@@ -0,0 +1,325 @@
+package client
+
+import "time"
+
+// Статусы PR
+const (
+	StatusOpen   = "OPEN"
+	StatusMerged = "MERGED"
+)
+
+// User - пользователь
+type User struct {
+	ID              string        `json:"id"`
+	Username        string        `json:"username"`
+	Email           string        `json:"-"` // заполняется только UpdateSettings
+	EmailConfigured bool          `json:"email_configured"`
+	ChatHandle      string        `json:"chat_handle,omitempty"`
+	TeamName        string        `json:"team_name"`
+	IsActive        bool          `json:"is_active"`
+	Teams           []string      `json:"teams"`
+	WorkSchedule    *WorkSchedule `json:"work_schedule,omitempty"`
+	MaxOpenReviews  *int          `json:"max_open_reviews,omitempty"` // nil - без ограничения
+	ReviewWeight    float64       `json:"review_weight,omitempty"`
+	Seniority       string        `json:"seniority,omitempty"` // junior, middle или senior
+	DigestOptOut    bool          `json:"digest_opt_out,omitempty"`
+	QuietHours      *QuietHours   `json:"quiet_hours,omitempty"`
+}
+
+// UserSettings - изменяемые настройки пользователя. nil-поля не изменяются
+type UserSettings struct {
+	ReviewWeight *float64 `json:"review_weight,omitempty"`
+	Seniority    *string  `json:"seniority,omitempty"`
+	DigestOptOut *bool    `json:"digest_opt_out,omitempty"`
+	Email        *string  `json:"email,omitempty"`       // "" удаляет адрес
+	ChatHandle   *string  `json:"chat_handle,omitempty"` // "" удаляет упоминание
+}
+
+// WorkSchedule - рабочее время пользователя в его часовом поясе
+type WorkSchedule struct {
+	Timezone string `json:"timezone"` // IANA, например "Europe/Berlin"
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+	Days     []int  `json:"days"`     // 1 - понедельник ... 7 - воскресенье
+}
+
+// QuietHours - время, когда пользователю не отправляются уведомления
+type QuietHours struct {
+	Timezone string `json:"timezone"` // IANA
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+}
+
+// Team - команда с участниками и её место в иерархии
+type Team struct {
+	TeamName              string      `json:"team_name"`
+	ParentTeamName        string      `json:"parent_team_name,omitempty"`
+	Ancestors             []string    `json:"ancestors,omitempty"`
+	Subteams              []*TeamNode `json:"subteams,omitempty"`
+	MinSeniorReviewers    int         `json:"min_senior_reviewers"` // сколько ревьюеров PR должны быть senior
+	ReviewSLA             *ReviewSLA  `json:"review_sla,omitempty"`
+	ChatWebhookConfigured bool        `json:"chat_webhook_configured"` // задан ли вебхук чата команды
+	Members               []*User     `json:"members"`
+}
+
+// Действия при нарушении SLA ревью
+const (
+	SLAActionReassign    = "reassign"     // заменить просрочившего ревьюера
+	SLAActionAddReviewer = "add_reviewer" // добавить ещё одного ревьюера
+)
+
+// ReviewSLA - срок ревью команды в рабочих часах (понедельник-пятница, UTC)
+type ReviewSLA struct {
+	Hours  int    `json:"hours"`
+	Action string `json:"action"`
+}
+
+// TeamNode - узел дерева команд. Total-счётчики включают все подкоманды
+type TeamNode struct {
+	TeamName          string      `json:"team_name"`
+	MembersCount      int         `json:"members_count"`
+	TotalMembersCount int         `json:"total_members_count"`
+	OpenPRsCount      int         `json:"open_prs_count"`
+	TotalOpenPRsCount int         `json:"total_open_prs_count"`
+	Children          []*TeamNode `json:"children"`
+}
+
+// TeamMember - участник в запросе на создание команды
+type TeamMember struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"` // пусто - сохранённый адрес не меняется
+	IsActive bool   `json:"is_active"`
+}
+
+// Политики для участников, которые уже состоят в других командах
+const (
+	TransferPolicyReject = "reject"
+	TransferPolicyMove   = "move"
+	TransferPolicySkip   = "skip"
+	TransferPolicyJoin   = "join"
+)
+
+// AddTeamRequest - запрос на создание команды
+type AddTeamRequest struct {
+	TeamName       string       `json:"team_name"`
+	ParentTeamName string       `json:"parent_team_name,omitempty"`
+	Members        []TeamMember `json:"members"`
+	TransferPolicy string       `json:"transfer_policy,omitempty"`
+	// MinSeniorReviewers - сколько из назначенных на PR ревьюеров должны быть senior
+	MinSeniorReviewers int `json:"min_senior_reviewers,omitempty"`
+}
+
+// MemberTransfer - участник, который состоял в другой команде
+type MemberTransfer struct {
+	UserID   string `json:"user_id"`
+	FromTeam string `json:"from_team"`
+	// Открытые PR прежней команды, с ревью которых снят перемещённый участник
+	ReleasedReviews []string `json:"released_reviews,omitempty"`
+}
+
+// TeamTransfers - участники, перемещённые из других команд, пропущенные
+// или добавленные с сохранением членства в других командах
+type TeamTransfers struct {
+	Moved   []MemberTransfer `json:"moved"`
+	Skipped []MemberTransfer `json:"skipped"`
+	Joined  []MemberTransfer `json:"joined"`
+}
+
+// AddTeamResult - результат создания команды
+type AddTeamResult struct {
+	Team      *Team          `json:"team"`
+	Transfers *TeamTransfers `json:"transfers"`
+}
+
+// UpdateTeamRequest - запрос на изменение команды
+type UpdateTeamRequest struct {
+	TeamName       string       `json:"team_name"`
+	NewTeamName    string       `json:"new_team_name,omitempty"`
+	AddMembers     []TeamMember `json:"add_members,omitempty"`
+	RemoveMembers  []string     `json:"remove_members,omitempty"`
+	TransferPolicy string       `json:"transfer_policy,omitempty"`
+	// nil - не менять, указатель на пустую строку - сделать команду корневой
+	ParentTeamName *string `json:"parent_team_name,omitempty"`
+	// nil - не менять
+	MinSeniorReviewers *int `json:"min_senior_reviewers,omitempty"`
+	// Входящий вебхук Slack/Mattermost: nil - не менять, указатель на пустую строку - удалить
+	ChatWebhookURL *string `json:"chat_webhook_url,omitempty"`
+}
+
+// ReviewAssignment - назначение ревьюера на PR
+type ReviewAssignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// TeamChanges - результат изменения или удаления команды
+type TeamChanges struct {
+	Moved           []MemberTransfer   `json:"moved"`
+	Skipped         []MemberTransfer   `json:"skipped"`
+	Joined          []MemberTransfer   `json:"joined"`
+	Removed         []string           `json:"removed"`
+	ReleasedReviews []ReviewAssignment `json:"released_reviews"`
+	// Подкоманды удалённой команды, перенесённые к её родителю
+	ReparentedSubteams []string `json:"reparented_subteams,omitempty"`
+}
+
+// UpdateTeamResult - результат изменения команды
+type UpdateTeamResult struct {
+	Team    *Team        `json:"team"`
+	Changes *TeamChanges `json:"changes"`
+}
+
+// PullRequest - PR с назначенными ревьюерами
+type PullRequest struct {
+	ID                string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name,omitempty"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	Version           int        `json:"version"`
+}
+
+// PullRequestShort - краткая информация о PR
+type PullRequestShort struct {
+	ID       string `json:"pull_request_id"`
+	Name     string `json:"pull_request_name"`
+	AuthorID string `json:"author_id"`
+	Status   string `json:"status"`
+}
+
+// CreatePullRequestRequest - запрос на создание PR
+type CreatePullRequestRequest struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	TeamName        string `json:"team_name,omitempty"` // обязательно, если автор состоит в нескольких командах
+}
+
+// CreatePullRequestResult - созданный PR и объяснение выбора ревьюеров
+type CreatePullRequestResult struct {
+	PullRequest *PullRequest           `json:"pull_request"`
+	Assignment  *AssignmentExplanation `json:"assignment"`
+}
+
+// PreviewPullRequestResult - ревьюеры, которые были бы назначены на новый PR автора
+type PreviewPullRequestResult struct {
+	AuthorID          string                 `json:"author_id"`
+	ProposedReviewers []string               `json:"proposed_reviewers"`
+	EligiblePool      []*User                `json:"eligible_pool"` // все подходящие кандидаты в порядке приоритета
+	Assignment        *AssignmentExplanation `json:"assignment"`
+}
+
+// ReassignResult - результат переназначения ревьюера
+type ReassignResult struct {
+	PullRequest *PullRequest           `json:"pull_request"`
+	ReplacedBy  string                 `json:"replaced_by"`
+	Assignment  *AssignmentExplanation `json:"assignment"`
+}
+
+// AssignmentExplanation - почему на PR назначены именно эти ревьюеры
+type AssignmentExplanation struct {
+	TeamName   string            `json:"team_name,omitempty"`
+	Strategy   string            `json:"strategy"`   // random или working_hours
+	PoolSize   int               `json:"pool_size"`  // участников команды
+	Candidates int               `json:"candidates"` // осталось после исключений
+	Excluded   []*Exclusion      `json:"excluded"`
+	Selected   []*ReviewerChoice `json:"selected"`
+	// Родительская команда, из которой выбраны ревьюеры, если в команде PR подходящих нет
+	FallbackTeam string `json:"fallback_team,omitempty"`
+	// Почему при создании PR назначено меньше 2 ревьюеров: no_team, no_candidates или at_capacity
+	Shortfall string `json:"shortfall,omitempty"`
+}
+
+// Exclusion - участник команды, исключённый из кандидатов.
+// Reason: author, already_assigned, forbidden, inactive, absent, at_capacity или not_senior
+type Exclusion struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// ReviewerChoice - выбранный ревьюер.
+// Reason: prefer_rule, senior_rule, weighted_random, working_hours или requested
+type ReviewerChoice struct {
+	UserID        string `json:"user_id"`
+	Reason        string `json:"reason"`
+	RecentReviews int    `json:"recent_reviews,omitempty"`
+}
+
+// UserReviews - PR'ы, где пользователь назначен ревьюером
+type UserReviews struct {
+	UserID       string              `json:"user_id"`
+	PullRequests []*PullRequestShort `json:"pull_requests"`
+}
+
+// Absence - период отсутствия пользователя [StartsAt, EndsAt)
+type Absence struct {
+	ID       int64     `json:"absence_id"`
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+// Типы правил для пары автор-ревьюер
+const (
+	ReviewerRulePrefer = "prefer" // ревьюер назначается автору в первую очередь
+	ReviewerRuleForbid = "forbid" // ревьюер никогда не назначается автору
+)
+
+// ReviewerRule - правило назначения ревьюера автору PR в команде
+type ReviewerRule struct {
+	TeamName   string    `json:"team_name"`
+	AuthorID   string    `json:"author_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	Kind       string    `json:"kind"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReviewEscalation - событие эскалации просроченного ревью
+type ReviewEscalation struct {
+	ID            int64     `json:"escalation_id"`
+	PullRequestID string    `json:"pull_request_id"`
+	ReviewerID    string    `json:"reviewer_id"`
+	Action        string    `json:"action"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"` // пусто - подходящего кандидата не нашлось
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Статусы запуска фоновой задачи
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// JobRun - запуск фоновой задачи
+type JobRun struct {
+	ID         int64      `json:"run_id"`
+	JobName    string     `json:"job_name"`
+	Trigger    string     `json:"trigger"` // schedule или manual
+	Status     string     `json:"status"`
+	Processed  int        `json:"processed"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// Job - фоновая задача сервиса
+type Job struct {
+	Name            string  `json:"job_name"`
+	Description     string  `json:"description"`
+	IntervalSeconds int64   `json:"interval_seconds"` // 0 - только ручной запуск
+	LastRun         *JobRun `json:"last_run"`
+}
+
+// AddAbsenceRequest - запрос на регистрацию отсутствия
+type AddAbsenceRequest struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason,omitempty"`
+}
@@ -5,24 +5,62 @@ import "errors"
 // Domain errors - используются в сервисах
 
 var (
-	ErrNotFound     = errors.New("not found")
-	ErrTeamExists   = errors.New("team already exists")
-	ErrPRExists     = errors.New("pull request already exists")
-	ErrPRMerged     = errors.New("cannot modify merged PR")
-	ErrNotAssigned  = errors.New("reviewer not assigned to PR")
-	ErrNoCandidate  = errors.New("no candidate available for reassignment")
-	ErrInvalidInput = errors.New("invalid input")
+	ErrNotFound        = errors.New("not found")
+	ErrTeamExists      = errors.New("team already exists")
+	ErrPRExists        = errors.New("pull request already exists")
+	ErrPRMerged        = errors.New("cannot modify merged PR")
+	ErrNotAssigned     = errors.New("reviewer not assigned to PR")
+	ErrNoCandidate     = errors.New("no candidate available for reassignment")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrVersionConflict = errors.New("pull request version does not match")
+	ErrUserInOtherTeam = errors.New("user belongs to another team")
+
+	// Ошибки ручного назначения ревьюера
+	ErrAlreadyAssigned        = errors.New("reviewer already assigned to PR")
+	ErrReviewerInactive       = errors.New("reviewer is not active")
+	ErrAuthorAsReviewer       = errors.New("author cannot review own PR")
+	ErrNotTeamMember          = errors.New("reviewer is not a member of PR team")
+	ErrTooManyReviewers       = errors.New("pull request already has maximum number of reviewers")
+	ErrReviewerAtCapacity     = errors.New("reviewer has reached open review limit")
+	ErrReviewerForbidden      = errors.New("reviewer is forbidden for PR author by team rule")
+	ErrSeniorReviewerRequired = errors.New("team rule requires a senior reviewer")
+	ErrReviewerAbsent         = errors.New("reviewer is absent")
+
+	ErrJobRunning = errors.New("job is already running")
+
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with different request")
+	ErrRequestInProgress    = errors.New("request with this idempotency key is in progress")
 )
 
 // Коды ошибок для API (из OpenAPI)
 
 const (
-	CodeTeamExists  = "TEAM_EXISTS"
-	CodePRExists    = "PR_EXISTS"
-	CodePRMerged    = "PR_MERGED"
-	CodeNotAssigned = "NOT_ASSIGNED"
-	CodeNoCandidate = "NO_CANDIDATE"
-	CodeNotFound    = "NOT_FOUND"
+	CodeTeamExists      = "TEAM_EXISTS"
+	CodePRExists        = "PR_EXISTS"
+	CodePRMerged        = "PR_MERGED"
+	CodeNotAssigned     = "NOT_ASSIGNED"
+	CodeNoCandidate     = "NO_CANDIDATE"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeUserInOtherTeam = "USER_IN_OTHER_TEAM"
+
+	CodeAlreadyAssigned        = "ALREADY_ASSIGNED"
+	CodeReviewerInactive       = "REVIEWER_INACTIVE"
+	CodeAuthorAsReviewer       = "AUTHOR_AS_REVIEWER"
+	CodeNotTeamMember          = "NOT_TEAM_MEMBER"
+	CodeTooManyReviewers       = "TOO_MANY_REVIEWERS"
+	CodeReviewerAtCapacity     = "REVIEWER_AT_CAPACITY"
+	CodeReviewerForbidden      = "REVIEWER_FORBIDDEN"
+	CodeSeniorReviewerRequired = "SENIOR_REVIEWER_REQUIRED"
+	CodeReviewerAbsent         = "REVIEWER_ABSENT"
+
+	CodeJobRunning = "JOB_RUNNING"
+
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	CodeRequestInProgress    = "REQUEST_IN_PROGRESS"
+
+	CodeInvalidRequest = "INVALID_REQUEST"
+	CodeInternalError  = "INTERNAL_ERROR"
 )
 
 // MapErrorToCode мапит доменную ошибку в API код ошибки
@@ -40,7 +78,88 @@ func MapErrorToCode(err error) string {
 		return CodeNoCandidate
 	case errors.Is(err, ErrNotFound):
 		return CodeNotFound
+	case errors.Is(err, ErrVersionConflict):
+		return CodeConflict
+	case errors.Is(err, ErrUserInOtherTeam):
+		return CodeUserInOtherTeam
+	case errors.Is(err, ErrAlreadyAssigned):
+		return CodeAlreadyAssigned
+	case errors.Is(err, ErrReviewerInactive):
+		return CodeReviewerInactive
+	case errors.Is(err, ErrAuthorAsReviewer):
+		return CodeAuthorAsReviewer
+	case errors.Is(err, ErrNotTeamMember):
+		return CodeNotTeamMember
+	case errors.Is(err, ErrTooManyReviewers):
+		return CodeTooManyReviewers
+	case errors.Is(err, ErrReviewerAtCapacity):
+		return CodeReviewerAtCapacity
+	case errors.Is(err, ErrReviewerForbidden):
+		return CodeReviewerForbidden
+	case errors.Is(err, ErrSeniorReviewerRequired):
+		return CodeSeniorReviewerRequired
+	case errors.Is(err, ErrReviewerAbsent):
+		return CodeReviewerAbsent
+	case errors.Is(err, ErrJobRunning):
+		return CodeJobRunning
+	case errors.Is(err, ErrIdempotencyKeyReused):
+		return CodeIdempotencyKeyReused
+	case errors.Is(err, ErrRequestInProgress):
+		return CodeRequestInProgress
+	case errors.Is(err, ErrInvalidInput):
+		return CodeInvalidRequest
+	default:
+		return CodeInternalError
+	}
+}
+
+// MapCodeToError мапит API код ошибки обратно в доменную ошибку.
+// Для неизвестных кодов возвращает nil
+func MapCodeToError(code string) error {
+	switch code {
+	case CodeTeamExists:
+		return ErrTeamExists
+	case CodePRExists:
+		return ErrPRExists
+	case CodePRMerged:
+		return ErrPRMerged
+	case CodeNotAssigned:
+		return ErrNotAssigned
+	case CodeNoCandidate:
+		return ErrNoCandidate
+	case CodeNotFound:
+		return ErrNotFound
+	case CodeConflict:
+		return ErrVersionConflict
+	case CodeUserInOtherTeam:
+		return ErrUserInOtherTeam
+	case CodeAlreadyAssigned:
+		return ErrAlreadyAssigned
+	case CodeReviewerInactive:
+		return ErrReviewerInactive
+	case CodeAuthorAsReviewer:
+		return ErrAuthorAsReviewer
+	case CodeNotTeamMember:
+		return ErrNotTeamMember
+	case CodeTooManyReviewers:
+		return ErrTooManyReviewers
+	case CodeReviewerAtCapacity:
+		return ErrReviewerAtCapacity
+	case CodeReviewerForbidden:
+		return ErrReviewerForbidden
+	case CodeSeniorReviewerRequired:
+		return ErrSeniorReviewerRequired
+	case CodeReviewerAbsent:
+		return ErrReviewerAbsent
+	case CodeJobRunning:
+		return ErrJobRunning
+	case CodeIdempotencyKeyReused:
+		return ErrIdempotencyKeyReused
+	case CodeRequestInProgress:
+		return ErrRequestInProgress
+	case CodeInvalidRequest:
+		return ErrInvalidInput
 	default:
-		return "INTERNAL_ERROR"
+		return nil
 	}
 }
@@ -14,6 +14,20 @@ func TestErrorConstants(t *testing.T) {
 	assert.NotNil(t, ErrNotAssigned)
 	assert.NotNil(t, ErrNoCandidate)
 	assert.NotNil(t, ErrInvalidInput)
+	assert.NotNil(t, ErrVersionConflict)
+	assert.NotNil(t, ErrUserInOtherTeam)
+	assert.NotNil(t, ErrAlreadyAssigned)
+	assert.NotNil(t, ErrReviewerInactive)
+	assert.NotNil(t, ErrAuthorAsReviewer)
+	assert.NotNil(t, ErrNotTeamMember)
+	assert.NotNil(t, ErrTooManyReviewers)
+	assert.NotNil(t, ErrReviewerAtCapacity)
+	assert.NotNil(t, ErrReviewerForbidden)
+	assert.NotNil(t, ErrSeniorReviewerRequired)
+	assert.NotNil(t, ErrReviewerAbsent)
+	assert.NotNil(t, ErrJobRunning)
+	assert.NotNil(t, ErrIdempotencyKeyReused)
+	assert.NotNil(t, ErrRequestInProgress)
 }
 
 func TestErrorCodes(t *testing.T) {
@@ -23,6 +37,20 @@ func TestErrorCodes(t *testing.T) {
 	assert.Equal(t, "PR_MERGED", CodePRMerged)
 	assert.Equal(t, "NOT_ASSIGNED", CodeNotAssigned)
 	assert.Equal(t, "NO_CANDIDATE", CodeNoCandidate)
+	assert.Equal(t, "CONFLICT", CodeConflict)
+	assert.Equal(t, "USER_IN_OTHER_TEAM", CodeUserInOtherTeam)
+	assert.Equal(t, "ALREADY_ASSIGNED", CodeAlreadyAssigned)
+	assert.Equal(t, "REVIEWER_INACTIVE", CodeReviewerInactive)
+	assert.Equal(t, "AUTHOR_AS_REVIEWER", CodeAuthorAsReviewer)
+	assert.Equal(t, "NOT_TEAM_MEMBER", CodeNotTeamMember)
+	assert.Equal(t, "TOO_MANY_REVIEWERS", CodeTooManyReviewers)
+	assert.Equal(t, "REVIEWER_AT_CAPACITY", CodeReviewerAtCapacity)
+	assert.Equal(t, "REVIEWER_FORBIDDEN", CodeReviewerForbidden)
+	assert.Equal(t, "SENIOR_REVIEWER_REQUIRED", CodeSeniorReviewerRequired)
+	assert.Equal(t, "REVIEWER_ABSENT", CodeReviewerAbsent)
+	assert.Equal(t, "JOB_RUNNING", CodeJobRunning)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", CodeIdempotencyKeyReused)
+	assert.Equal(t, "REQUEST_IN_PROGRESS", CodeRequestInProgress)
 }
 
 func TestMapErrorToCode(t *testing.T) {
@@ -37,6 +65,21 @@ func TestMapErrorToCode(t *testing.T) {
 		{"pr merged", ErrPRMerged, CodePRMerged},
 		{"not assigned", ErrNotAssigned, CodeNotAssigned},
 		{"no candidate", ErrNoCandidate, CodeNoCandidate},
+		{"version conflict", ErrVersionConflict, CodeConflict},
+		{"user in other team", ErrUserInOtherTeam, CodeUserInOtherTeam},
+		{"already assigned", ErrAlreadyAssigned, CodeAlreadyAssigned},
+		{"reviewer inactive", ErrReviewerInactive, CodeReviewerInactive},
+		{"author as reviewer", ErrAuthorAsReviewer, CodeAuthorAsReviewer},
+		{"not team member", ErrNotTeamMember, CodeNotTeamMember},
+		{"too many reviewers", ErrTooManyReviewers, CodeTooManyReviewers},
+		{"reviewer at capacity", ErrReviewerAtCapacity, CodeReviewerAtCapacity},
+		{"reviewer forbidden", ErrReviewerForbidden, CodeReviewerForbidden},
+		{"senior reviewer required", ErrSeniorReviewerRequired, CodeSeniorReviewerRequired},
+		{"reviewer absent", ErrReviewerAbsent, CodeReviewerAbsent},
+		{"job running", ErrJobRunning, CodeJobRunning},
+		{"idempotency key reused", ErrIdempotencyKeyReused, CodeIdempotencyKeyReused},
+		{"request in progress", ErrRequestInProgress, CodeRequestInProgress},
+		{"invalid input", ErrInvalidInput, CodeInvalidRequest},
 		{"unknown error", assert.AnError, "INTERNAL_ERROR"},
 	}
 
@@ -47,3 +90,39 @@ func TestMapErrorToCode(t *testing.T) {
 		})
 	}
 }
+
+func TestMapCodeToError(t *testing.T) {
+	errs := []error{
+		ErrNotFound,
+		ErrTeamExists,
+		ErrPRExists,
+		ErrPRMerged,
+		ErrNotAssigned,
+		ErrNoCandidate,
+		ErrVersionConflict,
+		ErrUserInOtherTeam,
+		ErrAlreadyAssigned,
+		ErrReviewerInactive,
+		ErrAuthorAsReviewer,
+		ErrNotTeamMember,
+		ErrTooManyReviewers,
+		ErrReviewerAtCapacity,
+		ErrReviewerForbidden,
+		ErrSeniorReviewerRequired,
+		ErrReviewerAbsent,
+		ErrJobRunning,
+		ErrIdempotencyKeyReused,
+		ErrRequestInProgress,
+		ErrInvalidInput,
+	}
+
+	// Коды должны однозначно мапиться в обе стороны
+	for _, err := range errs {
+		t.Run(err.Error(), func(t *testing.T) {
+			assert.Equal(t, err, MapCodeToError(MapErrorToCode(err)))
+		})
+	}
+
+	assert.Nil(t, MapCodeToError(CodeInternalError))
+	assert.Nil(t, MapCodeToError("UNKNOWN"))
+}